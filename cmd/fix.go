@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/kubepug/kubepug/lib"
+)
+
+var (
+	fixWrite bool
+
+	fixCmd = &cobra.Command{
+		Use:          "fix",
+		SilenceUsage: true,
+		Short:        "Rewrites the manifests using deprecated APIs to their replacements, printing an unified diff or writing the files in place",
+		Example:      "kubepug fix --input-file=./manifests --k8s-version=v1.25.0 --write",
+		Args:         cobra.NoArgs,
+		PreRunE:      completeFix,
+		RunE:         runFix,
+	}
+)
+
+func completeFix(cmd *cobra.Command, args []string) error {
+	if inputFile == "" || inputFile == "-" {
+		return fmt.Errorf("a file or directory should be defined on the input-file flag, STDIN cannot be fixed")
+	}
+
+	return Complete(cmd, args)
+}
+
+func runFix(cmd *cobra.Command, _ []string) error {
+	config := newConfig()
+	logrus.Debugf("Starting Kubepug fix with configs: %+v", config)
+	kubepug, err := lib.NewKubepug(&config)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := commandContext(cmd)
+	defer cancel()
+
+	fixes, err := kubepug.GetFixes(ctx)
+	if err != nil {
+		return err
+	}
+
+	var output bytes.Buffer
+	for _, fix := range fixes {
+		for _, object := range fix.Objects {
+			for _, warning := range object.Warnings {
+				logrus.Warningf("%s: %s %s: %s", fix.Location, object.Kind, object.Name, warning)
+			}
+		}
+
+		if !fix.Changed() {
+			continue
+		}
+
+		if fixWrite {
+			if err := fix.Write(); err != nil {
+				return err
+			}
+			fmt.Fprintf(&output, "fixed %s\n", fix.Location)
+			continue
+		}
+
+		diff, err := fix.Diff()
+		if err != nil {
+			return fmt.Errorf("failed to generate the diff of %s: %w", fix.Location, err)
+		}
+		output.WriteString(diff)
+	}
+
+	return writeOutput(output.Bytes())
+}
+
+func init() {
+	fixCmd.Flags().BoolVar(&fixWrite, "write", false, "Write the fixed manifests back to their files instead of printing an unified diff. Defaults to false")
+}
@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"golang.org/x/mod/semver"
+
+	"github.com/kubepug/kubepug/lib"
+	"github.com/kubepug/kubepug/pkg/formatter"
+)
+
+var (
+	planFrom string
+	planTo   string
+
+	planFormatter formatter.PlanFormatter
+
+	planCmd = &cobra.Command{
+		Use:          "plan",
+		SilenceUsage: true,
+		Short:        "Plans an upgrade across many Kubernetes minor versions, showing on which version each API in use is deprecated and removed, and until when each migration can be deferred",
+		Example:      "kubepug plan --from=v1.25.0 --to=v1.29.0",
+		Args:         cobra.NoArgs,
+		PreRunE:      completePlan,
+		RunE:         runPlan,
+	}
+)
+
+func completePlan(cmd *cobra.Command, args []string) error {
+	var errComplete error
+	var err error
+
+	if planTo != "" {
+		k8sVersion = planTo
+	}
+
+	if !semver.IsValid(planFrom) {
+		errComplete = errors.Join(errComplete, fmt.Errorf("invalid current Kubernetes version, should be a valid semantic version"))
+	}
+
+	if !semver.IsValid(k8sVersion) {
+		errComplete = errors.Join(errComplete, fmt.Errorf("invalid target Kubernetes version, should be a valid semantic version"))
+	} else if semver.IsValid(planFrom) && semver.Compare(semver.MajorMinor(planFrom), semver.MajorMinor(k8sVersion)) >= 0 {
+		errComplete = errors.Join(errComplete, fmt.Errorf("target Kubernetes version should be newer than the current version"))
+	}
+
+	planFormatter, err = formatter.NewPlanFormatterWithError(format)
+	if err != nil {
+		errComplete = errors.Join(errComplete, err)
+	}
+
+	if errComplete != nil {
+		return errComplete
+	}
+
+	return Complete(cmd, args)
+}
+
+func runPlan(cmd *cobra.Command, _ []string) error {
+	config := newConfig()
+	logrus.Debugf("Starting Kubepug plan with configs: %+v", config)
+	kubepug, err := lib.NewKubepug(&config)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := commandContext(cmd)
+	defer cancel()
+
+	plan, err := kubepug.GetPlan(ctx, planFrom)
+	if err != nil {
+		return err
+	}
+
+	bytes, err := planFormatter.OutputPlan(*plan)
+	if err != nil {
+		return err
+	}
+	return writeOutput(bytes)
+}
+
+func init() {
+	planCmd.Flags().StringVar(&planFrom, "from", "", "Kubernetes version currently running, like v1.25.0")
+	planCmd.Flags().StringVar(&planTo, "to", "", "Kubernetes version to upgrade to, like v1.29.0. Defaults to the --k8s-version flag")
+	planCmd.MarkFlagRequired("from") //nolint: errcheck
+}
@@ -1,38 +1,88 @@
 package cmd
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"golang.org/x/mod/semver"
 
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 
 	"github.com/kubepug/kubepug/lib"
 	"github.com/kubepug/kubepug/pkg/formatter"
+	fileinput "github.com/kubepug/kubepug/pkg/kubepug/input/file"
+	helminput "github.com/kubepug/kubepug/pkg/kubepug/input/helm"
+	k8sinput "github.com/kubepug/kubepug/pkg/kubepug/input/k8s"
+	kustomizeinput "github.com/kubepug/kubepug/pkg/kubepug/input/kustomize"
+	"github.com/kubepug/kubepug/pkg/results"
 
 	// Import the Kubernetes Authentication plugin
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 	"sigs.k8s.io/release-utils/version"
 )
 
+const (
+	loadRestrictionsRootOnly = "LoadRestrictionsRootOnly"
+	loadRestrictionsNone     = "LoadRestrictionsNone"
+)
+
 var (
 	kubernetesConfigFlags *genericclioptions.ConfigFlags
 
 	generatedStore    string
 	k8sVersion        string
+	fromVersion       string
 	forceDownload     bool
 	errorOnDeprecated bool
 	errorOnDeleted    bool
 	swaggerDir        string
 	format            string
+	skipDeprecated    bool
+	outputTemplate    string
+	columns           []string
+	sortBy            string
 	filename          string
 	inputFile         string
+	recursive         bool
+	followSymlinks    bool
+	includePatterns   []string
+	excludePatterns   []string
+	helmChart         string
+	helmValues        []string
+	helmSet           []string
+	helmReleaseName   string
+	helmNamespace     string
+	kustomization     string
+	loadRestrictor    string
+	helmReleases      bool
+	managedFields     bool
+	fields            bool
+	rules             bool
+	includeNamespaces []string
+	excludeNamespaces []string
+	labelSelector     string
+	fieldSelector     string
+	qps               float32
+	burst             int
+	concurrency       int
+	pageSize          int64
+	contexts          []string
+	allContexts       bool
+	timeout           time.Duration
+	auditLog          string
+	metricsFile       string
+	crds              bool
+	crdFiles          string
+	apiserverMetrics  bool
 	logLevel          string
 
 	outputFormatter formatter.Formatter
@@ -61,7 +111,28 @@ func Complete(_ *cobra.Command, _ []string) error {
 		errComplete = errors.Join(errComplete, fmt.Errorf("invalid Kubernetes version, should be 'master' or a valid semantic version"))
 	}
 
-	outputFormatter, err = formatter.NewFormatterWithError(format)
+	if timeout < 0 {
+		errComplete = errors.Join(errComplete, fmt.Errorf("invalid timeout, should not be negative"))
+	}
+
+	if fromVersion != "" && !semver.IsValid(fromVersion) {
+		errComplete = errors.Join(errComplete, fmt.Errorf("invalid current Kubernetes version, should be a valid semantic version"))
+	}
+
+	if set := setInputFlags(); len(set) > 1 {
+		errComplete = errors.Join(errComplete, fmt.Errorf("only one input can be analysed at a time, but %s were set", strings.Join(set, ", ")))
+	}
+
+	if loadRestrictor != loadRestrictionsRootOnly && loadRestrictor != loadRestrictionsNone {
+		errComplete = errors.Join(errComplete, fmt.Errorf("invalid kustomize load restrictor, should be %s or %s", loadRestrictionsRootOnly, loadRestrictionsNone))
+	}
+
+	outputFormatter, err = formatter.NewFormatterWithConfig(format, formatter.Config{
+		SkipDeprecated: skipDeprecated,
+		Template:       outputTemplate,
+		Columns:        columns,
+		SortBy:         sortBy,
+	})
 	if err != nil {
 		errComplete = errors.Join(errComplete, err)
 	}
@@ -83,21 +154,102 @@ func Complete(_ *cobra.Command, _ []string) error {
 	return nil
 }
 
-func runPug(_ *cobra.Command, _ []string) error {
-	config := lib.Config{
+// setInputFlags returns the input flags that are set. When none is set, the cluster is analysed
+func setInputFlags() []string {
+	inputs := []struct{ flag, value string }{
+		{"--input-file", inputFile},
+		{"--helm-chart", helmChart},
+		{"--kustomize", kustomization},
+		{"--audit-log", auditLog},
+		{"--metrics-file", metricsFile},
+	}
+	set := make([]string, 0)
+	for _, input := range inputs {
+		if input.value != "" {
+			set = append(set, input.flag)
+		}
+	}
+	return set
+}
+
+// newConfig returns the library configuration defined by the command flags
+func newConfig() lib.Config {
+	return lib.Config{
 		GeneratedStore: generatedStore,
 		K8sVersion:     k8sVersion,
+		FromVersion:    fromVersion,
 		ConfigFlags:    kubernetesConfigFlags,
-		Input:          inputFile,
+		HelmReleases:   helmReleases,
+		ManagedFields:  managedFields,
+		Fields:         fields,
+		Rules:          rules,
+		Selector: k8sinput.Selector{
+			IncludeNamespaces: includeNamespaces,
+			ExcludeNamespaces: excludeNamespaces,
+			LabelSelector:     labelSelector,
+			FieldSelector:     fieldSelector,
+		},
+		QPS:              qps,
+		Burst:            burst,
+		Concurrency:      concurrency,
+		PageSize:         pageSize,
+		Contexts:         contexts,
+		AllContexts:      allContexts,
+		Input:            inputFile,
+		AuditLog:         auditLog,
+		CRDs:             crds,
+		CRDFiles:         crdFiles,
+		MetricsFile:      metricsFile,
+		APIServerMetrics: apiserverMetrics,
+		InputWalk: fileinput.WalkConfig{
+			Recursive:      recursive,
+			Include:        includePatterns,
+			Exclude:        excludePatterns,
+			FollowSymlinks: followSymlinks,
+		},
+		Chart: helminput.Config{
+			Chart:       helmChart,
+			ValueFiles:  helmValues,
+			Values:      helmSet,
+			ReleaseName: helmReleaseName,
+			Namespace:   helmNamespace,
+		},
+		Kustomization: kustomizeinput.Config{
+			Path:                 kustomization,
+			LoadRestrictionsNone: loadRestrictor == loadRestrictionsNone,
+		},
+	}
+}
+
+// commandContext returns the context of the command, limited by the timeout flag
+func commandContext(cmd *cobra.Command) (context.Context, context.CancelFunc) {
+	if timeout > 0 {
+		return context.WithTimeout(cmd.Context(), timeout)
 	}
+	return context.WithCancel(cmd.Context())
+}
 
+// writeOutput saves the output to the file defined by the filename flag, or prints it to stdout
+func writeOutput(bytes []byte) error {
+	if filename != "" {
+		return os.WriteFile(filename, bytes, 0o644) //nolint: gosec
+	}
+	fmt.Printf("%s", string(bytes))
+	return nil
+}
+
+func runPug(cmd *cobra.Command, _ []string) error {
+	config := newConfig()
 	logrus.Debugf("Starting Kubepug with configs: %+v", config)
 	kubepug, err := lib.NewKubepug(&config)
 	if err != nil {
 		return err
 	}
 
-	result, err := kubepug.GetDeprecated()
+	ctx, cancel := commandContext(cmd)
+	defer cancel()
+
+	result, err := kubepug.GetDeprecated(ctx)
 	if err != nil {
 		return err
 	}
@@ -108,20 +260,21 @@ func runPug(_ *cobra.Command, _ []string) error {
 		return err
 	}
 
-	if filename != "" {
-		err = os.WriteFile(filename, bytes, 0o644) //nolint: gosec
-		if err != nil {
-			return err
-		}
-	} else {
-		fmt.Printf("%s", string(bytes))
+	if err := writeOutput(bytes); err != nil {
+		return err
+	}
+
+	var errRun error
+	// The clusters that failed are only reported on the summary, so the run is failed as their APIs are unknown
+	if failed := results.FailedClusters(result); len(failed) > 0 {
+		errRun = errors.Join(errRun, fmt.Errorf("failed to scan %d of %d clusters: %s", len(failed), len(result.Clusters), strings.Join(failed, ", ")))
 	}
 
 	if (errorOnDeleted && len(result.DeletedAPIs) > 0) || (errorOnDeprecated && len(result.DeprecatedAPIs) > 0) {
-		return fmt.Errorf("found %d Deleted APIs and %d Deprecated APIs", len(result.DeletedAPIs), len(result.DeprecatedAPIs))
+		errRun = errors.Join(errRun, fmt.Errorf("found %d Deleted APIs and %d Deprecated APIs", len(result.DeletedAPIs), len(result.DeprecatedAPIs)))
 	}
 
-	return nil
+	return errRun
 }
 
 func init() {
@@ -133,38 +286,83 @@ func init() {
 
 	kubernetesConfigFlags = genericclioptions.NewConfigFlags(true)
 	kubernetesConfigFlags.AddFlags(rootCmd.Flags())
+	kubernetesConfigFlags.AddFlags(planCmd.Flags())
 
-	rootCmd.Flags().MarkHidden("as")                       //nolint: errcheck
-	rootCmd.Flags().MarkHidden("as-group")                 //nolint: errcheck
-	rootCmd.Flags().MarkHidden("cache-dir")                //nolint: errcheck
-	rootCmd.Flags().MarkHidden("certificate-authority")    //nolint: errcheck
-	rootCmd.Flags().MarkHidden("client-certificate")       //nolint: errcheck
-	rootCmd.Flags().MarkHidden("client-key")               //nolint: errcheck
-	rootCmd.Flags().MarkHidden("insecure-skip-tls-verify") //nolint: errcheck
-	rootCmd.Flags().MarkHidden("namespace")                //nolint: errcheck
-	rootCmd.Flags().MarkHidden("request-timeout")          //nolint: errcheck
-	rootCmd.Flags().MarkHidden("server")                   //nolint: errcheck
-	rootCmd.Flags().MarkHidden("token")                    //nolint: errcheck
-	rootCmd.Flags().MarkHidden("user")                     //nolint: errcheck
+	// The plan command scans the cluster as well, so it hides the same kubeconfig flags
+	for _, flags := range []*pflag.FlagSet{rootCmd.Flags(), planCmd.Flags()} {
+		flags.MarkHidden("as")                       //nolint: errcheck
+		flags.MarkHidden("as-group")                 //nolint: errcheck
+		flags.MarkHidden("cache-dir")                //nolint: errcheck
+		flags.MarkHidden("certificate-authority")    //nolint: errcheck
+		flags.MarkHidden("client-certificate")       //nolint: errcheck
+		flags.MarkHidden("client-key")               //nolint: errcheck
+		flags.MarkHidden("insecure-skip-tls-verify") //nolint: errcheck
+		flags.MarkHidden("request-timeout")          //nolint: errcheck
+		flags.MarkHidden("server")                   //nolint: errcheck
+		flags.MarkHidden("token")                    //nolint: errcheck
+		flags.MarkHidden("user")                     //nolint: errcheck
+	}
 
 	rootCmd.PersistentFlags().BoolVar(&errorOnDeprecated, "error-on-deprecated", false, "If a deprecated object is found, the program will exit with return code 1 instead of 0. Defaults to false")
 	rootCmd.PersistentFlags().BoolVar(&errorOnDeleted, "error-on-deleted", false, "If a deleted object is found, the program will exit with return code 1 instead of 0. Defaults to false")
 	rootCmd.PersistentFlags().StringVar(&k8sVersion, "k8s-version", "master", "Which Kubernetes release version (https://github.com/kubernetes/kubernetes/releases) should be used to validate objects. Defaults to master")
+	rootCmd.PersistentFlags().StringVar(&fromVersion, "from-version", "", "Kubernetes version currently running, used to tell if the replacement of each API can already be used. Detected from the cluster when not set")
 	rootCmd.PersistentFlags().StringVar(&swaggerDir, "swagger-dir", "", "Where to keep swagger.json downloaded file. If not provided will use the system temporary directory")
 	rootCmd.PersistentFlags().BoolVar(&forceDownload, "force-download", false, "Whether to force the download of a new swagger.json file even if one exists. Defaults to false")
-	rootCmd.PersistentFlags().StringVar(&format, "format", "stdout", "Format in which the list will be displayed [stdout, plain, json, yaml]")
+	rootCmd.PersistentFlags().StringVar(&format, "format", "stdout", "Format in which the list will be displayed [stdout, plain, json, yaml, sarif, junit, markdown, html, csv, table, github, gitlab, template]")
+	rootCmd.PersistentFlags().BoolVar(&skipDeprecated, "junit-skip-deprecated", false, "Report the deprecated APIs as skipped test cases instead of failures on the junit format. Defaults to false")
+	rootCmd.PersistentFlags().StringSliceVar(&columns, "columns", []string{}, "Columns of the csv and table formats. Defaults to STATUS,GROUP,VERSION,KIND,NAMESPACE,NAME,LOCATION,REPLACEMENT,K8S-VERSION")
+	rootCmd.PersistentFlags().StringVar(&sortBy, "sort-by", "", "Column the rows of the csv and table formats are sorted by")
+	rootCmd.PersistentFlags().StringVar(&outputTemplate, "template", "", "Go template, or the location of a file containing it, used to render the results on the template format")
 	rootCmd.PersistentFlags().StringVar(&filename, "filename", "", "Name of the file the results will be saved to, if empty it will display to stdout")
 	rootCmd.PersistentFlags().StringVar(&inputFile, "input-file", "", "Location of a file or directory containing k8s manifests to be analysed. Use \"-\" to read from STDIN")
+	rootCmd.PersistentFlags().StringVar(&auditLog, "audit-log", "", "Location of a Kubernetes audit log file (JSON lines) to find the clients calling deprecated APIs. Use \"-\" to read from STDIN")
+	rootCmd.PersistentFlags().StringVar(&metricsFile, "metrics-file", "", "Location of a file containing the API Server metrics (Prometheus text format) to find the deprecated APIs that were requested. Use \"-\" to read from STDIN")
+	rootCmd.PersistentFlags().BoolVar(&apiserverMetrics, "apiserver-metrics", false, "Also analyse the deprecated APIs requested to the API Server, as exposed on its /metrics endpoint. Defaults to false")
+	rootCmd.PersistentFlags().BoolVar(&recursive, "recursive", false, "If the input-file is a directory, also analyse the files inside its subdirectories. Defaults to false")
+	rootCmd.PersistentFlags().StringSliceVar(&includePatterns, "include", []string{}, "Glob patterns (like **/*.yaml) of the files inside the input-file directory that should be analysed. Patterns without a \"/\" are matched against the file name only")
+	rootCmd.PersistentFlags().StringSliceVar(&excludePatterns, "exclude", []string{}, "Glob patterns (like .git or node_modules) of the files and directories inside the input-file directory that should be skipped. Patterns without a \"/\" are matched against the file name only")
+	rootCmd.PersistentFlags().BoolVar(&followSymlinks, "follow-symlinks", false, "If the input-file is a directory, also traverse symbolic links pointing to directories. Defaults to false")
+	rootCmd.PersistentFlags().StringVar(&helmChart, "helm-chart", "", "Location of a Helm chart directory or packaged chart (.tgz) to be rendered and analysed")
+	rootCmd.PersistentFlags().StringSliceVar(&helmValues, "helm-values", []string{}, "Values files used to render the helm-chart, the same as Helm --values flag")
+	rootCmd.PersistentFlags().StringArrayVar(&helmSet, "helm-set", []string{}, "Values used to render the helm-chart on the key=value format, the same as Helm --set flag")
+	rootCmd.PersistentFlags().StringVar(&helmReleaseName, "helm-release-name", "release-name", "Release name used to render the helm-chart")
+	rootCmd.PersistentFlags().StringVar(&helmNamespace, "helm-namespace", "default", "Namespace used to render the helm-chart")
+	rootCmd.PersistentFlags().BoolVar(&managedFields, "managed-fields", false, "Also report the field managers (like controllers or CI tools) that still write objects using deprecated APIs. Requires listing all the objects of the cluster. Defaults to false")
+	rootCmd.PersistentFlags().BoolVar(&fields, "fields", false, "Also report the deprecated and deleted fields used by the objects, like spec.serviceAccount of Pods. On a cluster, requires listing the whole objects of the APIs with deprecated fields. Defaults to false")
+	rootCmd.PersistentFlags().BoolVar(&rules, "rules", false, "Also analyse the rules of webhook configurations, RBAC roles and APIServices referencing deprecated APIs. Defaults to false")
+	rootCmd.PersistentFlags().StringSliceVar(&includeNamespaces, "include-namespaces", []string{}, "Namespaces whose objects should be analysed. When set, cluster scoped objects are not analysed. Defaults to all the namespaces")
+	rootCmd.PersistentFlags().StringSliceVar(&excludeNamespaces, "exclude-namespaces", []string{}, "Namespaces whose objects should not be analysed")
+	rootCmd.PersistentFlags().StringVarP(&labelSelector, "selector", "l", "", "Label selector used to filter the objects analysed on the cluster, the same as kubectl --selector flag")
+	rootCmd.PersistentFlags().StringVar(&fieldSelector, "field-selector", "", "Field selector used to filter the objects analysed on the cluster, the same as kubectl --field-selector flag")
+	rootCmd.PersistentFlags().DurationVar(&timeout, "timeout", 0, "Maximum duration of the whole analysis, like 5m. Zero means no timeout")
+	rootCmd.PersistentFlags().StringSliceVar(&contexts, "contexts", []string{}, "Kubeconfig contexts whose clusters should be analysed in parallel, merging their results")
+	rootCmd.PersistentFlags().BoolVar(&allContexts, "all-contexts", false, "Analyse the clusters of all the kubeconfig contexts in parallel, merging their results. Defaults to false")
+	rootCmd.PersistentFlags().Float32Var(&qps, "qps", 50, "Maximum queries per second sent to the API Server while analysing the cluster")
+	rootCmd.PersistentFlags().IntVar(&burst, "burst", 100, "Maximum burst of queries sent to the API Server while analysing the cluster")
+	rootCmd.PersistentFlags().IntVar(&concurrency, "concurrency", 4, "How many resources are listed from the cluster, and how many clusters are scanned, at the same time")
+	rootCmd.PersistentFlags().Int64Var(&pageSize, "page-size", 500, "How many objects are requested on each list call to the cluster")
+	rootCmd.PersistentFlags().BoolVar(&helmReleases, "helm-releases", false, "Also analyse the manifests of the Helm releases stored on the cluster, as removed APIs on them break the next helm upgrade. Defaults to false")
+	rootCmd.PersistentFlags().StringVar(&kustomization, "kustomize", "", "Location of a directory containing a kustomization file to be built and analysed")
+	rootCmd.PersistentFlags().StringVar(&loadRestrictor, "kustomize-load-restrictor", loadRestrictionsRootOnly, "If set to LoadRestrictionsNone, the kustomization can load files outside of its root [LoadRestrictionsRootOnly, LoadRestrictionsNone]")
+	rootCmd.PersistentFlags().BoolVar(&crds, "crds", false, "Also use the CustomResourceDefinitions of the cluster to find deprecated versions of custom resources. Defaults to false")
+	rootCmd.PersistentFlags().StringVar(&crdFiles, "crd-files", "", "Location of a file or directory containing CustomResourceDefinitions manifests used to find deprecated versions of custom resources")
 	rootCmd.PersistentFlags().StringVarP(&logLevel, "verbosity", "v", logrus.WarnLevel.String(), "Log level: debug, info, warn, error, fatal, panic")
 	rootCmd.PersistentFlags().StringVar(&generatedStore, "database", "https://kubepug.xyz/data/data.json", "Sets the generated database location. Can be remote file or local")
 	rootCmd.AddCommand(version.WithFont("starwars"))
+	rootCmd.AddCommand(planCmd)
+	rootCmd.AddCommand(fixCmd)
 
 	rootCmd.PersistentFlags().MarkDeprecated("swagger-dir", "flag is deprecated and will be removed on next version. database flag should be used instead") //nolint: errcheck
 	rootCmd.PersistentFlags().MarkDeprecated("force-download", "flag is deprecated and will be removed on next version. This flag is no-op.")               //nolint: errcheck
 }
 
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
+	// Interrupting kubepug cancels the requests being made, instead of waiting for them
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if err := rootCmd.ExecuteContext(ctx); err != nil {
 		logrus.Errorf("An error has occurred: %v", err)
 		os.Exit(1)
 	}
@@ -0,0 +1,152 @@
+package deprecations
+
+import (
+	"reflect"
+	"strings"
+
+	"k8s.io/gengo/v2/types"
+)
+
+// maxFieldDepth limits how deep the members of an API type are walked
+const maxFieldDepth = 20
+
+// deprecatedFields walks the members of an API type, returning the fields marked as deprecated. A field
+// is deprecated when its Go name starts with Deprecated (like DeprecatedServiceAccount) or when its comments
+// contain a "Deprecated:" line. The versions are defined by the deprecated and removed tags on the field
+// comments. Without the tags, the versions are left empty, as it is not known since when the field is deprecated
+func deprecatedFields(t *types.Type) ([]FieldDeprecation, error) {
+	w := &fieldWalker{
+		visiting: make(map[*types.Type]struct{}),
+	}
+	if err := w.walk(t, nil); err != nil {
+		return nil, err
+	}
+	return w.fields, nil
+}
+
+type fieldWalker struct {
+	// visiting contains the types of the current path, so recursive types (like JSONSchemaProps)
+	// don't make us walk forever
+	visiting map[*types.Type]struct{}
+	fields   []FieldDeprecation
+}
+
+func (w *fieldWalker) walk(t *types.Type, path []string) error {
+	if t == nil || len(path) > maxFieldDepth {
+		return nil
+	}
+
+	switch t.Kind {
+	case types.Pointer:
+		return w.walk(t.Elem, path)
+	case types.Alias:
+		return w.walk(t.Underlying, path)
+	case types.Slice, types.Array, types.Map:
+		return w.walk(t.Elem, appendPath(path, "*"))
+	case types.Struct:
+	default:
+		return nil
+	}
+
+	if _, ok := w.visiting[t]; ok {
+		return nil
+	}
+	w.visiting[t] = struct{}{}
+	defer delete(w.visiting, t)
+
+	for i := range t.Members {
+		member := &t.Members[i]
+		name, inline := jsonName(member)
+		if name == "-" {
+			continue
+		}
+		if inline {
+			if err := w.walk(member.Type, path); err != nil {
+				return err
+			}
+			continue
+		}
+
+		fieldPath := appendPath(path, name)
+		if !isDeprecatedMember(member) {
+			if err := w.walk(member.Type, fieldPath); err != nil {
+				return err
+			}
+			continue
+		}
+
+		field, err := w.newField(member, fieldPath)
+		if err != nil {
+			return err
+		}
+		w.fields = append(w.fields, field)
+	}
+	return nil
+}
+
+func (w *fieldWalker) newField(member *types.Member, path []string) (FieldDeprecation, error) {
+	field := FieldDeprecation{
+		Path: path,
+	}
+
+	descriptions := make([]string, 0, len(member.CommentLines))
+	for _, line := range member.CommentLines {
+		if !strings.HasPrefix(strings.TrimSpace(line), "+") {
+			descriptions = append(descriptions, line)
+		}
+	}
+	field.Description = strings.Join(descriptions, "\n")
+
+	if extractTag(deprecatedTagName, member.CommentLines) != nil {
+		_, major, minor, err := parseKubeVersionTag(deprecatedTagName, member, member.CommentLines)
+		if err != nil {
+			return field, err
+		}
+		field.DeprecatedVersion = Version{VersionMajor: major, VersionMinor: minor}
+	}
+
+	if extractTag(removedTagName, member.CommentLines) != nil {
+		_, major, minor, err := parseKubeVersionTag(removedTagName, member, member.CommentLines)
+		if err != nil {
+			return field, err
+		}
+		field.RemovedVersion = Version{VersionMajor: major, VersionMinor: minor}
+	}
+
+	return field, nil
+}
+
+// jsonName returns the name of a member on the JSON object, and if its fields are inlined
+// on the parent object (like TypeMeta)
+func jsonName(member *types.Member) (name string, inline bool) {
+	tag := strings.Split(reflect.StructTag(member.Tags).Get("json"), ",")
+	name = tag[0]
+	for _, option := range tag[1:] {
+		if option == "inline" {
+			return name, true
+		}
+	}
+	if name == "" {
+		if member.Embedded {
+			return "", true
+		}
+		name = member.Name
+	}
+	return name, false
+}
+
+func isDeprecatedMember(member *types.Member) bool {
+	if strings.HasPrefix(member.Name, "Deprecated") || strings.HasPrefix(member.Name, "ZZZ_Deprecated") {
+		return true
+	}
+	for _, line := range member.CommentLines {
+		if strings.HasPrefix(strings.ToLower(strings.TrimSpace(line)), "deprecated:") {
+			return true
+		}
+	}
+	return false
+}
+
+func appendPath(path []string, element string) []string {
+	return append(append(make([]string, 0, len(path)+1), path...), element)
+}
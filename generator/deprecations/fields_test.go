@@ -0,0 +1,90 @@
+package deprecations
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/gengo/v2/types"
+)
+
+func TestDeprecatedFields(t *testing.T) {
+	stringType := &types.Type{Name: types.Name{Name: "string"}, Kind: types.Builtin}
+
+	container := &types.Type{
+		Name: types.Name{Name: "Container"},
+		Kind: types.Struct,
+		Members: []types.Member{
+			{Name: "Name", Tags: `json:"name"`, Type: stringType},
+			{
+				Name: "LegacyFlag",
+				Tags: `json:"legacyFlag,omitempty"`,
+				CommentLines: []string{
+					"Deprecated: this flag is not used anymore.",
+					"+k8s:prerelease-lifecycle-gen:deprecated=1.20",
+					"+k8s:prerelease-lifecycle-gen:removed=1.25",
+				},
+				Type: stringType,
+			},
+		},
+	}
+
+	// Props references itself, and should not be walked forever
+	props := &types.Type{Name: types.Name{Name: "Props"}, Kind: types.Struct}
+	props.Members = []types.Member{
+		{Name: "Items", Tags: `json:"items,omitempty"`, Type: &types.Type{Kind: types.Pointer, Elem: props}},
+	}
+
+	podSpec := &types.Type{
+		Name: types.Name{Name: "PodSpec"},
+		Kind: types.Struct,
+		Members: []types.Member{
+			{Name: "ServiceAccountName", Tags: `json:"serviceAccountName,omitempty"`, Type: stringType},
+			{
+				Name: "DeprecatedServiceAccount",
+				Tags: `json:"serviceAccount,omitempty"`,
+				CommentLines: []string{
+					"DeprecatedServiceAccount is a deprecated alias for ServiceAccountName.",
+				},
+				Type: stringType,
+			},
+			{Name: "Containers", Tags: `json:"containers"`, Type: &types.Type{Kind: types.Slice, Elem: container}},
+			{Name: "Props", Tags: `json:"props,omitempty"`, Type: props},
+			{Name: "Internal", Tags: `json:"-"`, Type: container},
+		},
+	}
+
+	deployment := &types.Type{
+		Name: types.Name{Name: "Deployment"},
+		Kind: types.Struct,
+		Members: []types.Member{
+			{
+				Name:     "TypeMeta",
+				Embedded: true,
+				Tags:     `json:",inline"`,
+				Type: &types.Type{Kind: types.Struct, Members: []types.Member{
+					{Name: "Kind", Tags: `json:"kind,omitempty"`, Type: stringType},
+				}},
+			},
+			{Name: "Spec", Tags: `json:"spec,omitempty"`, Type: &types.Type{Kind: types.Struct, Members: []types.Member{
+				{Name: "Template", Tags: `json:"template"`, Type: &types.Type{Kind: types.Struct, Members: []types.Member{
+					{Name: "Spec", Tags: `json:"spec,omitempty"`, Type: podSpec},
+				}}},
+			}}},
+		},
+	}
+
+	fields, err := deprecatedFields(deployment)
+	require.NoError(t, err)
+	require.Equal(t, []FieldDeprecation{
+		{
+			Path:        []string{"spec", "template", "spec", "serviceAccount"},
+			Description: "DeprecatedServiceAccount is a deprecated alias for ServiceAccountName.",
+		},
+		{
+			Path:              []string{"spec", "template", "spec", "containers", "*", "legacyFlag"},
+			Description:       "Deprecated: this flag is not used anymore.",
+			DeprecatedVersion: Version{VersionMajor: 1, VersionMinor: 20},
+			RemovedVersion:    Version{VersionMajor: 1, VersionMinor: 25},
+		},
+	}, fields)
+}
@@ -62,22 +62,27 @@ func tagExists(tagName string, t *types.Type) bool {
 
 func extractKubeVersionTag(tagName string, t *types.Type) (*tagValue, int, int, error) {
 	comments := append(append([]string{}, t.SecondClosestCommentLines...), t.CommentLines...)
+	return parseKubeVersionTag(tagName, t, comments)
+}
+
+// parseKubeVersionTag parses a xx.yy version tag from the comments of a type or a member
+func parseKubeVersionTag(tagName string, owner fmt.Stringer, comments []string) (*tagValue, int, int, error) {
 	rawTag := extractTag(tagName, comments)
 	if rawTag == nil || len(rawTag.value) == 0 {
-		return nil, -1, -1, fmt.Errorf("%v missing %v=Version tag", t, tagName)
+		return nil, -1, -1, fmt.Errorf("%v missing %v=Version tag", owner, tagName)
 	}
 
 	splitValue := strings.Split(rawTag.value, ".")
 	if len(splitValue) != 2 || len(splitValue[0]) == 0 || len(splitValue[1]) == 0 {
-		return nil, -1, -1, fmt.Errorf("%v format must match %v=xx.yy tag", t, tagName)
+		return nil, -1, -1, fmt.Errorf("%v format must match %v=xx.yy tag", owner, tagName)
 	}
 	major, err := strconv.ParseInt(splitValue[0], 10, 32)
 	if err != nil {
-		return nil, -1, -1, fmt.Errorf("%v format must match %v=xx.yy : %w", t, tagName, err)
+		return nil, -1, -1, fmt.Errorf("%v format must match %v=xx.yy : %w", owner, tagName, err)
 	}
 	minor, err := strconv.ParseInt(splitValue[1], 10, 32)
 	if err != nil {
-		return nil, -1, -1, fmt.Errorf("%v format must match %v=xx.yy : %w", t, tagName, err)
+		return nil, -1, -1, fmt.Errorf("%v format must match %v=xx.yy : %w", owner, tagName, err)
 	}
 
 	return rawTag, int(major), int(minor), nil
@@ -63,6 +63,11 @@ func (g *genPreleaseLifecycle) GenerateType(c *generator.Context, t *types.Type,
 
 	reg.Description = strings.Join(t.CommentLines, "\n")
 
+	reg.Fields, err = deprecatedFields(t)
+	if err != nil {
+		return err
+	}
+
 	reg.Group = g.group
 	reg.Version = g.version
 	reg.Kind = t.Name.Name
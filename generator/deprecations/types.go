@@ -13,9 +13,20 @@ type Version struct {
 
 type APIDeprecation struct {
 	GroupVersionKind
-	Description       string           `json:"description,omitempty"`
-	IntroducedVersion Version          `json:"introduced_version,omitempty"`
-	DeprecatedVersion Version          `json:"deprecated_version,omitempty"`
-	RemovedVersion    Version          `json:"removed_version,omitempty"`
-	Replacement       GroupVersionKind `json:"replacement,omitempty"`
+	Description       string             `json:"description,omitempty"`
+	IntroducedVersion Version            `json:"introduced_version,omitempty"`
+	DeprecatedVersion Version            `json:"deprecated_version,omitempty"`
+	RemovedVersion    Version            `json:"removed_version,omitempty"`
+	Replacement       GroupVersionKind   `json:"replacement,omitempty"`
+	Fields            []FieldDeprecation `json:"fields,omitempty"`
+}
+
+// FieldDeprecation is a deprecated or removed field of an API
+type FieldDeprecation struct {
+	// Path is the path of the field on the object, like ["spec", "template", "spec", "serviceAccount"].
+	// A "*" element matches any item of a list or any key of a map
+	Path              []string `json:"path"`
+	Description       string   `json:"description,omitempty"`
+	DeprecatedVersion Version  `json:"deprecated_version,omitempty"`
+	RemovedVersion    Version  `json:"removed_version,omitempty"`
 }
@@ -0,0 +1,178 @@
+package lib
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/discovery"
+
+	"github.com/kubepug/kubepug/pkg/results"
+	"github.com/kubepug/kubepug/pkg/store/generatedstore"
+)
+
+// contexts returns the kubeconfig contexts that should be scanned, being all the contexts
+// of the kubeconfig when AllContexts is set
+func (k *Kubepug) contexts() ([]string, error) {
+	if len(k.Config.Contexts) == 0 && !k.Config.AllContexts {
+		return nil, nil
+	}
+	if !k.usesCluster() {
+		return nil, fmt.Errorf("contexts can only be used when scanning clusters")
+	}
+	if !k.Config.AllContexts {
+		return k.Config.Contexts, nil
+	}
+	if k.Config.ConfigFlags == nil {
+		return nil, fmt.Errorf("k8s config cannot be null when k8s is being used")
+	}
+
+	rawConfig, err := k.Config.ConfigFlags.ToRawKubeConfigLoader().RawConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the kubeconfig contexts: %w", err)
+	}
+
+	contexts := make([]string, 0, len(rawConfig.Contexts))
+	for name := range rawConfig.Contexts {
+		contexts = append(contexts, name)
+	}
+	if len(contexts) == 0 {
+		return nil, fmt.Errorf("no context found on the kubeconfig")
+	}
+	sort.Strings(contexts)
+	return contexts, nil
+}
+
+// defaultClusterConcurrency is how many clusters are scanned at the same time when Concurrency is not set
+const defaultClusterConcurrency = 4
+
+// getClustersResults scans the contexts in parallel, up to Concurrency at the same time, and merges
+// their results recording the cluster of each item. A cluster that fails to be scanned is reported on the cluster summary,
+// and an error is only returned if no cluster could be scanned
+func (k *Kubepug) getClustersResults(ctx context.Context, storer *generatedstore.GeneratedStore, contexts []string) (*results.Result, error) {
+	clusterResults := make([]results.Result, len(contexts))
+	clusterErrors := make([]error, 0)
+	var errorsLock sync.Mutex
+
+	concurrency := k.Config.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultClusterConcurrency
+	}
+	group := errgroup.Group{}
+	group.SetLimit(concurrency)
+	for i, kubeContext := range contexts {
+		group.Go(func() error {
+			config := *k.Config
+			config.ConfigFlags = withContext(k.Config.ConfigFlags, kubeContext)
+			config.Contexts = nil
+			config.AllContexts = false
+			cluster := &Kubepug{Config: &config}
+
+			result, err := cluster.getClusterResults(ctx, storer)
+			if err != nil {
+				logrus.Warningf("failed to scan cluster %s: %s", kubeContext, err)
+				clusterResults[i].Clusters = []results.ClusterSummary{{Name: kubeContext, Error: err.Error()}}
+
+				errorsLock.Lock()
+				clusterErrors = append(clusterErrors, fmt.Errorf("cluster %s: %w", kubeContext, err))
+				errorsLock.Unlock()
+				return nil
+			}
+
+			results.SetCluster(result, kubeContext)
+			result.Clusters = []results.ClusterSummary{results.NewClusterSummary(kubeContext, result)}
+			clusterResults[i] = *result
+			return nil
+		})
+	}
+	_ = group.Wait()
+
+	if len(clusterErrors) == len(contexts) {
+		return nil, errors.Join(clusterErrors...)
+	}
+
+	merged := results.MergeResults(clusterResults...)
+	return &merged, nil
+}
+
+// getClusterResults scans a single cluster, using its CustomResourceDefinitions when enabled
+func (k *Kubepug) getClusterResults(ctx context.Context, generated *generatedstore.GeneratedStore) (*results.Result, error) {
+	generated = k.withClusterVersion(generated)
+	storer, err := k.withCRDStores(ctx, generated)
+	if err != nil {
+		return nil, err
+	}
+	return k.getResults(ctx, storer)
+}
+
+// withClusterVersion returns a store telling if the replacements are available on the version of
+// the cluster being scanned. The store is kept as is when the version can't be detected
+func (k *Kubepug) withClusterVersion(generated *generatedstore.GeneratedStore) *generatedstore.GeneratedStore {
+	if k.Config.FromVersion != "" || !k.usesCluster() || k.Config.ConfigFlags == nil {
+		return generated
+	}
+
+	configRest, err := k.restConfig()
+	if err != nil {
+		logrus.Warningf("failed to detect the Kubernetes version of the cluster: %s", err)
+		return generated
+	}
+	disco, err := discovery.NewDiscoveryClientForConfig(configRest)
+	if err != nil {
+		logrus.Warningf("failed to detect the Kubernetes version of the cluster: %s", err)
+		return generated
+	}
+	info, err := disco.ServerVersion()
+	if err != nil {
+		logrus.Warningf("failed to detect the Kubernetes version of the cluster: %s", err)
+		return generated
+	}
+
+	clusterStore, err := generated.WithFromVersion(info.GitVersion)
+	if err != nil {
+		logrus.Warningf("failed to parse the Kubernetes version of the cluster %s: %s", info.GitVersion, err)
+		return generated
+	}
+	logrus.Infof("verifying the replacements available on the cluster version %s", info.GitVersion)
+	return clusterStore
+}
+
+// usesCluster tells if the cluster is the input being verified, instead of files or logs
+func (k *Kubepug) usesCluster() bool {
+	return k.Config.Input == "" && k.Config.Chart.Chart == "" && k.Config.Kustomization.Path == "" &&
+		k.Config.AuditLog == "" && k.Config.MetricsFile == ""
+}
+
+// withContext returns a copy of the kubeconfig flags selecting a different context
+func withContext(flags *genericclioptions.ConfigFlags, kubeContext string) *genericclioptions.ConfigFlags {
+	contextFlags := genericclioptions.NewConfigFlags(true)
+	if flags != nil {
+		contextFlags.CacheDir = flags.CacheDir
+		contextFlags.KubeConfig = flags.KubeConfig
+		contextFlags.ClusterName = flags.ClusterName
+		contextFlags.AuthInfoName = flags.AuthInfoName
+		contextFlags.Namespace = flags.Namespace
+		contextFlags.APIServer = flags.APIServer
+		contextFlags.TLSServerName = flags.TLSServerName
+		contextFlags.Insecure = flags.Insecure
+		contextFlags.CertFile = flags.CertFile
+		contextFlags.KeyFile = flags.KeyFile
+		contextFlags.CAFile = flags.CAFile
+		contextFlags.BearerToken = flags.BearerToken
+		contextFlags.Impersonate = flags.Impersonate
+		contextFlags.ImpersonateUID = flags.ImpersonateUID
+		contextFlags.ImpersonateGroup = flags.ImpersonateGroup
+		contextFlags.Username = flags.Username
+		contextFlags.Password = flags.Password
+		contextFlags.Timeout = flags.Timeout
+		contextFlags.DisableCompression = flags.DisableCompression
+		contextFlags.WrapConfigFn = flags.WrapConfigFn
+	}
+	contextFlags.Context = &kubeContext
+	return contextFlags
+}
@@ -1,18 +1,29 @@
 package lib
 
 import (
+	"context"
 	"fmt"
 
+	"golang.org/x/mod/semver"
+
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/metadata"
 	"k8s.io/client-go/rest"
 
+	"github.com/kubepug/kubepug/pkg/fixer"
 	"github.com/kubepug/kubepug/pkg/kubepug"
+	auditinput "github.com/kubepug/kubepug/pkg/kubepug/input/audit"
 	fileinput "github.com/kubepug/kubepug/pkg/kubepug/input/file"
+	helminput "github.com/kubepug/kubepug/pkg/kubepug/input/helm"
 	k8sinput "github.com/kubepug/kubepug/pkg/kubepug/input/k8s"
+	kustomizeinput "github.com/kubepug/kubepug/pkg/kubepug/input/kustomize"
+	metricsinput "github.com/kubepug/kubepug/pkg/kubepug/input/metrics"
+	"github.com/kubepug/kubepug/pkg/planner"
 	"github.com/kubepug/kubepug/pkg/results"
 	"github.com/kubepug/kubepug/pkg/store"
+	"github.com/kubepug/kubepug/pkg/store/crdstore"
 	"github.com/kubepug/kubepug/pkg/store/generatedstore"
 )
 
@@ -27,8 +38,77 @@ type Config struct {
 	// Should be on the Kubernetes semver format: v1.24.5
 	K8sVersion string
 
-	Input       string
+	// FromVersion defines the Kubernetes version currently running, used to tell if the replacement
+	// of each API can already be used. When empty and a cluster is scanned, the cluster version is used
+	FromVersion string
+
+	Input string
+	// InputWalk defines how Input should be traversed when it is a directory
+	InputWalk fileinput.WalkConfig
+
+	// Chart defines a Helm Chart that should be rendered and used as the input. When the chart
+	// does not define a KubeVersion, K8sVersion is exposed to the templates
+	Chart helminput.Config
+
+	// Kustomization defines a Kustomization directory that should be built and used as the input
+	Kustomization kustomizeinput.Config
+
+	// MetricsFile defines a Prometheus text exposition file, or "-" for STDIN, containing the API Server
+	// metrics of the requested deprecated APIs that should be verified
+	MetricsFile string
+
+	// APIServerMetrics defines if the deprecated APIs requested to the API Server, as exposed on its metrics,
+	// should also be verified when a cluster is being used
+	APIServerMetrics bool
+
+	// CRDs defines if the CustomResourceDefinitions of the cluster should be used to find
+	// deprecated versions of custom resources
+	CRDs bool
+
+	// CRDFiles defines a file or directory containing CustomResourceDefinitions manifests that
+	// should be used to find deprecated versions of custom resources
+	CRDFiles string
+
+	// AuditLog defines an audit log file, or "-" for STDIN, containing the API calls that should be verified
+	AuditLog string
+
 	ConfigFlags *genericclioptions.ConfigFlags
+
+	// HelmReleases defines if the manifests stored by Helm on the cluster should also be verified
+	HelmReleases bool
+
+	// ManagedFields defines if the API versions used by the managers that wrote the objects should also be verified
+	ManagedFields bool
+
+	// Fields defines if the deprecated and deleted fields used by the objects should also be verified
+	Fields bool
+
+	// Rules defines if the webhook configurations, RBAC roles and APIServices referencing deprecated
+	// APIs should also be verified
+	Rules bool
+
+	// Selector restricts the namespaces and the objects listed from the cluster. When no namespace
+	// is included, the namespace defined on ConfigFlags (if any) is used
+	Selector k8sinput.Selector
+
+	// QPS and Burst define the client side rate limit used while talking to the API Server.
+	// When zero, the client-go defaults are used
+	QPS   float32
+	Burst int
+
+	// Concurrency defines how many resources are listed from the cluster at the same time, and how
+	// many clusters are scanned at the same time when using multiple contexts
+	Concurrency int
+
+	// PageSize defines how many objects are requested on each list call to the cluster
+	PageSize int64
+
+	// Contexts defines the kubeconfig contexts that should be scanned. The clusters are scanned
+	// in parallel and their results merged, recording the cluster of each item
+	Contexts []string
+
+	// AllContexts defines that all the contexts of the kubeconfig should be scanned
+	AllContexts bool
 }
 
 // Kubepug defines a kubepug instance to be used
@@ -44,10 +124,81 @@ func NewKubepug(config *Config) (*Kubepug, error) {
 	return &Kubepug{Config: config}, nil
 }
 
-// GetDeprecated returns the list of deprecated APIs
-func (k *Kubepug) GetDeprecated() (result *results.Result, err error) {
-	var storer store.DefinitionStorer
+// GetDeprecated returns the list of deprecated APIs. The scan is aborted when the context is canceled
+func (k *Kubepug) GetDeprecated(ctx context.Context) (result *results.Result, err error) {
+	storer, err := k.newGeneratedStore(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return k.scan(ctx, storer)
+}
+
+// GetPlan returns the plan to upgrade from the current version to the Kubernetes version
+// defined on Config, going through every minor version between them
+func (k *Kubepug) GetPlan(ctx context.Context, current string) (*planner.Plan, error) {
+	if k.Config == nil {
+		return nil, fmt.Errorf("config cannot be null")
+	}
+	if !semver.IsValid(k.Config.K8sVersion) {
+		return nil, fmt.Errorf("the Kubernetes version to upgrade to should be a valid semantic version")
+	}
+
+	// The replacements are verified against the version being upgraded from
+	config := *k.Config
+	if config.FromVersion == "" {
+		config.FromVersion = current
+	}
+	planPug := &Kubepug{Config: &config}
+
+	storer, err := planPug.newGeneratedStore(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := planPug.scan(ctx, storer)
+	if err != nil {
+		return nil, err
+	}
+
+	return planner.NewPlan(ctx, storer, current, k.Config.K8sVersion, result)
+}
+
+// GetFixes rewrites the manifests of the file input using deprecated APIs to their replacements,
+// returning the fix of each file where a deprecated API was found. The files are not written
+func (k *Kubepug) GetFixes(ctx context.Context) ([]*fixer.FileFix, error) {
+	if k.Config == nil {
+		return nil, fmt.Errorf("config cannot be null")
+	}
+	if k.Config.Input == "" || k.Config.Input == "-" {
+		return nil, fmt.Errorf("a file or directory input is required to fix manifests")
+	}
+
+	generated, err := k.newGeneratedStore(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	storer, err := k.withCRDStores(ctx, generated)
+	if err != nil {
+		return nil, err
+	}
+
+	input, err := fileinput.NewFileInputWithConfig(k.Config.Input, k.Config.InputWalk, storer)
+	if err != nil {
+		return nil, err
+	}
+
+	deprecated, deleted, err := input.GetDeprecations(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return fixer.NewFixer(append(deprecated, deleted...)...).Fix()
+}
 
+// newGeneratedStore reads the database, comparing the APIs with the Kubernetes version defined on Config
+func (k *Kubepug) newGeneratedStore(ctx context.Context) (*generatedstore.GeneratedStore, error) {
 	if k.Config == nil {
 		return nil, fmt.Errorf("config cannot be null")
 	}
@@ -56,35 +207,109 @@ func (k *Kubepug) GetDeprecated() (result *results.Result, err error) {
 		return nil, fmt.Errorf("a database path should be provided")
 	}
 
-	storer, err = generatedstore.NewGeneratedStore(generatedstore.StoreConfig{
-		Path:       k.Config.GeneratedStore,
-		MinVersion: k.Config.K8sVersion,
+	return generatedstore.NewGeneratedStore(ctx, generatedstore.StoreConfig{
+		Path:        k.Config.GeneratedStore,
+		MinVersion:  k.Config.K8sVersion,
+		FromVersion: k.Config.FromVersion,
 	})
+}
+
+// scan compares the inputs defined on Config with the store, scanning each one of the
+// contexts when more than one cluster should be used
+func (k *Kubepug) scan(ctx context.Context, storer *generatedstore.GeneratedStore) (*results.Result, error) {
+	contexts, err := k.contexts()
 	if err != nil {
 		return nil, err
 	}
+	if len(contexts) > 0 {
+		return k.getClustersResults(ctx, storer, contexts)
+	}
 
-	result, err = k.getResults(storer)
-	if err != nil {
-		return nil, err
+	return k.getClusterResults(ctx, storer)
+}
+
+// withCRDStores chains the generated store with the stores populated by CustomResourceDefinitions
+func (k *Kubepug) withCRDStores(ctx context.Context, storer store.DefinitionStorer) (store.DefinitionStorer, error) {
+	stores := []store.DefinitionStorer{storer}
+
+	if k.Config.CRDFiles != "" {
+		crdStore, err := crdstore.NewCRDStoreFromFiles(k.Config.CRDFiles)
+		if err != nil {
+			return nil, fmt.Errorf("error reading CRD files: %s", err)
+		}
+		stores = append(stores, crdStore)
+	}
+
+	if k.Config.CRDs {
+		if k.Config.ConfigFlags == nil {
+			return nil, fmt.Errorf("k8s config cannot be null when k8s is being used")
+		}
+		configRest, err := k.restConfig()
+		if err != nil {
+			return nil, fmt.Errorf("failed to create the K8s config parameters while listing CRDs: %w", err)
+		}
+		client, err := dynamic.NewForConfig(configRest)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create the K8s client while listing CRDs: %w", err)
+		}
+		crdStore, err := crdstore.NewCRDStoreFromCluster(ctx, client)
+		if err != nil {
+			return nil, err
+		}
+		stores = append(stores, crdStore)
 	}
 
-	return result, nil
+	if len(stores) == 1 {
+		return storer, nil
+	}
+	return store.NewChainStore(stores...), nil
 }
 
-func (k *Kubepug) getResults(storer store.DefinitionStorer) (*results.Result, error) {
+func (k *Kubepug) getResults(ctx context.Context, storer store.DefinitionStorer) (*results.Result, error) {
 	var inputMode kubepug.Deprecator
+	// inputs are the additional Deprecators whose results are merged with the inputMode ones
+	var inputs []kubepug.Deprecator
 	var err error
-	if k.Config.Input != "" {
-		inputMode, err = fileinput.NewFileInput(k.Config.Input, storer)
+	if k.Config.Chart.Chart != "" {
+		chart := k.Config.Chart
+		if chart.KubeVersion == "" && semver.IsValid(k.Config.K8sVersion) {
+			chart.KubeVersion = k.Config.K8sVersion
+		}
+		chart.Fields = k.Config.Fields
+		inputMode, err = helminput.NewHelmInput(chart, storer)
+		if err != nil {
+			return nil, fmt.Errorf("error reading helm chart input: %s", err)
+		}
+	} else if k.Config.Kustomization.Path != "" {
+		kustomization := k.Config.Kustomization
+		kustomization.Fields = k.Config.Fields
+		inputMode, err = kustomizeinput.NewKustomizeInput(kustomization, storer)
+		if err != nil {
+			return nil, fmt.Errorf("error reading kustomize input: %s", err)
+		}
+	} else if k.Config.AuditLog != "" {
+		inputMode, err = auditinput.NewAuditInput(k.Config.AuditLog, storer)
+		if err != nil {
+			return nil, fmt.Errorf("error reading audit log input: %s", err)
+		}
+	} else if k.Config.MetricsFile != "" {
+		inputMode, err = metricsinput.NewMetricsInput(k.Config.MetricsFile, storer)
 		if err != nil {
+			return nil, fmt.Errorf("error reading metrics input: %s", err)
+		}
+	} else if k.Config.Input != "" {
+		fileInput := fileinput.NewFileInputFromItems(make(fileinput.FileItems), storer)
+		// Fields is enabled before the files are read, so the body of the objects is kept
+		fileInput.Fields = k.Config.Fields
+		if err := fileInput.AddLocation(k.Config.Input, k.Config.InputWalk); err != nil {
 			return nil, fmt.Errorf("error reading file input: %s", err)
 		}
+		inputMode = fileInput
 	} else {
 		if k.Config.ConfigFlags == nil {
 			return nil, fmt.Errorf("k8s config cannot be null when k8s is being used")
 		}
-		configRest, err := k.Config.ConfigFlags.ToRESTConfig()
+		configRest, err := k.restConfig()
 		if err != nil {
 			return nil, fmt.Errorf("failed to create the K8s config parameters while listing Deprecated objects: %w", err)
 		}
@@ -95,6 +320,11 @@ func (k *Kubepug) getResults(storer store.DefinitionStorer) (*results.Result, er
 			return nil, fmt.Errorf("failed to create the K8s client while listing Deprecated objects: %w", err)
 		}
 
+		metadataClient, err := metadata.NewForConfig(configRest)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create the K8s metadata client while listing Deprecated objects: %w", err)
+		}
+
 		// Feed the KubeAPIs with the resourceName as this is used to the K8s Resource lister
 		disco, err := discovery.NewDiscoveryClientForConfig(configRest)
 		if err != nil {
@@ -105,16 +335,47 @@ func (k *Kubepug) getResults(storer store.DefinitionStorer) (*results.Result, er
 			K8sconfig:          k.Config.ConfigFlags,
 			Store:              storer,
 			Client:             client,
+			MetadataClient:     metadataClient,
 			DiscoveryClient:    disco,
-			IncludePrefixGroup: []string{".k8s.io"},
-			// The groups below are: externaldns (not core), anything on x-k8s.io, internal flowcontrol and the autoscaling group that is actually a CRD (the real autoscaling is just autoscaling/version)
-			IgnoreExactGroup: []string{"externaldns.k8s.io", "x-k8s.io", "flowcontrol.apiserver.k8s.io", "autoscaling.k8s.io"},
+			IncludePrefixGroup: store.IncludeGroups(storer, []string{".k8s.io"}),
+			IgnoreExactGroup:   store.DefaultIgnoreGroups(),
+			HelmReleases:       k.Config.HelmReleases,
+			ManagedFields:      k.Config.ManagedFields,
+			Fields:             k.Config.Fields,
+			Rules:              k.Config.Rules,
+			Selector:           k.Config.Selector,
+			Concurrency:        k.Config.Concurrency,
+			PageSize:           k.Config.PageSize,
+		}
+
+		if k.Config.APIServerMetrics {
+			metricsInput, err := metricsinput.NewMetricsInputFromCluster(ctx, disco.RESTClient(), storer)
+			if err != nil {
+				return nil, err
+			}
+			inputs = append(inputs, metricsInput)
 		}
 	}
 
-	output, err := kubepug.GetDeprecations(inputMode)
+	output, err := kubepug.GetDeprecations(ctx, append([]kubepug.Deprecator{inputMode}, inputs...)...)
 	if err != nil {
 		return nil, err
 	}
 	return &output, nil
 }
+
+// restConfig returns the configuration used to connect to the cluster, with the
+// rate limit defined on Config
+func (k *Kubepug) restConfig() (*rest.Config, error) {
+	configRest, err := k.Config.ConfigFlags.ToRESTConfig()
+	if err != nil {
+		return nil, err
+	}
+	if k.Config.QPS > 0 {
+		configRest.QPS = k.Config.QPS
+	}
+	if k.Config.Burst > 0 {
+		configRest.Burst = k.Config.Burst
+	}
+	return configRest, nil
+}
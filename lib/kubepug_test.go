@@ -1,11 +1,18 @@
 package lib
 
 import (
+	"context"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
 
+	helminput "github.com/kubepug/kubepug/pkg/kubepug/input/helm"
+	kustomizeinput "github.com/kubepug/kubepug/pkg/kubepug/input/kustomize"
+	"github.com/kubepug/kubepug/pkg/results"
+	"github.com/kubepug/kubepug/pkg/store/generatedstore"
 	"github.com/kubepug/kubepug/pkg/store/mock"
 	"github.com/stretchr/testify/require"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
@@ -83,7 +90,7 @@ func TestGetDeprecated(t *testing.T) {
 
 	t.Run("nil config should fail", func(t *testing.T) {
 		pug := &Kubepug{}
-		result, err := pug.GetDeprecated()
+		result, err := pug.GetDeprecated(context.Background())
 		require.ErrorContains(t, err, "config cannot be null")
 		require.Nil(t, result)
 	})
@@ -94,7 +101,7 @@ func TestGetDeprecated(t *testing.T) {
 				GeneratedStore: "",
 			},
 		}
-		result, err := pug.GetDeprecated()
+		result, err := pug.GetDeprecated(context.Background())
 		require.Error(t, err)
 		require.ErrorContains(t, err, "a database path should be provided")
 		require.Nil(t, result)
@@ -106,7 +113,7 @@ func TestGetDeprecated(t *testing.T) {
 				GeneratedStore: "/tmp123/lalala",
 			},
 		}
-		result, err := pug.GetDeprecated()
+		result, err := pug.GetDeprecated(context.Background())
 		require.Error(t, err)
 		require.ErrorContains(t, err, "open /tmp123/lalala: no such file or directory")
 		require.Nil(t, result)
@@ -118,7 +125,7 @@ func TestGetDeprecated(t *testing.T) {
 				GeneratedStore: ts.URL + "/notfound.json",
 			},
 		}
-		result, err := pug.GetDeprecated()
+		result, err := pug.GetDeprecated(context.Background())
 		require.Error(t, err)
 		require.ErrorContains(t, err, "could not download the data file")
 		require.Nil(t, result)
@@ -133,12 +140,140 @@ func TestGetDeprecated(t *testing.T) {
 			},
 		}
 
-		result, err := pug.GetDeprecated()
+		result, err := pug.GetDeprecated(context.Background())
 		require.Error(t, err)
 		require.ErrorContains(t, err, "error reading file input: input location /tmp123/lslslasd does not exist")
 		require.Nil(t, result)
 	})
 
+	t.Run("invalid helm chart input should fail", func(t *testing.T) {
+		pug := &Kubepug{
+			Config: &Config{
+				GeneratedStore: ts.URL + dataJSON,
+				K8sVersion:     "v1.22",
+				Chart: helminput.Config{
+					Chart: "/tmp123/lslslasd",
+				},
+			},
+		}
+
+		result, err := pug.GetDeprecated(context.Background())
+		require.Error(t, err)
+		require.ErrorContains(t, err, "error reading helm chart input: failed to load chart /tmp123/lslslasd")
+		require.Nil(t, result)
+	})
+
+	t.Run("helm chart should be rendered with the target version", func(t *testing.T) {
+		pug := &Kubepug{
+			Config: &Config{
+				GeneratedStore: ts.URL + dataJSON,
+				K8sVersion:     "v1.22",
+				Chart: helminput.Config{
+					Chart: "../test/testdata/charts/sample",
+				},
+			},
+		}
+
+		result, err := pug.GetDeprecated(context.Background())
+		require.NoError(t, err)
+		require.Len(t, result.DeletedAPIs, 1)
+		require.Equal(t, "templates/daemonset.yaml", result.DeletedAPIs[0].Items[0].Location)
+	})
+
+	t.Run("invalid kustomize input should fail", func(t *testing.T) {
+		pug := &Kubepug{
+			Config: &Config{
+				GeneratedStore: ts.URL + dataJSON,
+				K8sVersion:     "v1.22",
+				Kustomization: kustomizeinput.Config{
+					Path: "/tmp123/lslslasd",
+				},
+			},
+		}
+
+		result, err := pug.GetDeprecated(context.Background())
+		require.Error(t, err)
+		require.ErrorContains(t, err, "error reading kustomize input: failed to build kustomization /tmp123/lslslasd")
+		require.Nil(t, result)
+	})
+
+	t.Run("invalid audit log input should fail", func(t *testing.T) {
+		pug := &Kubepug{
+			Config: &Config{
+				GeneratedStore: ts.URL + dataJSON,
+				K8sVersion:     "v1.22",
+				AuditLog:       "/tmp123/lslslasd",
+			},
+		}
+
+		result, err := pug.GetDeprecated(context.Background())
+		require.Error(t, err)
+		require.ErrorContains(t, err, "error reading audit log input: failed to open audit log /tmp123/lslslasd")
+		require.Nil(t, result)
+	})
+
+	t.Run("audit log should report the callers", func(t *testing.T) {
+		pug := &Kubepug{
+			Config: &Config{
+				GeneratedStore: ts.URL + dataJSON,
+				K8sVersion:     "v1.22",
+				AuditLog:       "../test/testdata/audit/audit.log",
+			},
+		}
+
+		result, err := pug.GetDeprecated(context.Background())
+		require.NoError(t, err)
+		require.Len(t, result.DeletedAPIs, 2)
+	})
+
+	t.Run("invalid metrics input should fail", func(t *testing.T) {
+		pug := &Kubepug{
+			Config: &Config{
+				GeneratedStore: ts.URL + dataJSON,
+				K8sVersion:     "v1.22",
+				MetricsFile:    "/tmp123/lslslasd",
+			},
+		}
+
+		result, err := pug.GetDeprecated(context.Background())
+		require.Error(t, err)
+		require.ErrorContains(t, err, "error reading metrics input: failed to open metrics file /tmp123/lslslasd")
+		require.Nil(t, result)
+	})
+
+	t.Run("invalid CRD files should fail", func(t *testing.T) {
+		pug := &Kubepug{
+			Config: &Config{
+				GeneratedStore: ts.URL + dataJSON,
+				K8sVersion:     "v1.22",
+				CRDFiles:       "/tmp123/lslslasd",
+			},
+		}
+
+		result, err := pug.GetDeprecated(context.Background())
+		require.Error(t, err)
+		require.ErrorContains(t, err, "error reading CRD files: failed to read CRDs location /tmp123/lslslasd")
+		require.Nil(t, result)
+	})
+
+	t.Run("deprecated custom resources should be found using CRD files", func(t *testing.T) {
+		pug := &Kubepug{
+			Config: &Config{
+				GeneratedStore: ts.URL + dataJSON,
+				K8sVersion:     "v1.22",
+				CRDFiles:       "../test/testdata/crds",
+				Input:          "../test/testdata/customresources",
+			},
+		}
+
+		result, err := pug.GetDeprecated(context.Background())
+		require.NoError(t, err)
+		require.Len(t, result.DeprecatedAPIs, 1)
+		require.Equal(t, "Widget", result.DeprecatedAPIs[0].Kind)
+		require.Equal(t, "v1beta1", result.DeprecatedAPIs[0].Version)
+		require.Equal(t, "legacy-widget", result.DeprecatedAPIs[0].Items[0].ObjectName)
+	})
+
 	t.Run("empty k8s config should fail", func(t *testing.T) {
 		pug := &Kubepug{
 			Config: &Config{
@@ -147,7 +282,7 @@ func TestGetDeprecated(t *testing.T) {
 			},
 		}
 
-		result, err := pug.GetDeprecated()
+		result, err := pug.GetDeprecated(context.Background())
 		require.Error(t, err)
 		require.ErrorContains(t, err, "k8s config cannot be null when k8s is being used")
 		require.Nil(t, result)
@@ -164,9 +299,231 @@ func TestGetDeprecated(t *testing.T) {
 			},
 		}
 
-		result, err := pug.GetDeprecated()
+		result, err := pug.GetDeprecated(context.Background())
 		require.Error(t, err)
 		require.ErrorContains(t, err, "failed to create the K8s config parameters while listing Deprecated objects: stat /blabla123/kconfig: no such file or directory")
 		require.Nil(t, result)
 	})
 }
+
+func writeKubeconfig(t *testing.T, servers map[string]string) string {
+	t.Helper()
+	kubeconfig := "apiVersion: v1\nkind: Config\nclusters:\n"
+	for name, server := range servers {
+		kubeconfig += "- name: " + name + "\n  cluster:\n    server: " + server + "\n"
+	}
+	kubeconfig += "contexts:\n"
+	for name := range servers {
+		kubeconfig += "- name: " + name + "\n  context:\n    cluster: " + name + "\n"
+	}
+	kubeconfig += "users: []\n"
+
+	location := filepath.Join(t.TempDir(), "kubeconfig")
+	require.NoError(t, os.WriteFile(location, []byte(kubeconfig), 0o600))
+	return location
+}
+
+func newClusterServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	responses := map[string]string{
+		"/version": `{"major":"1","minor":"21","gitVersion":"v1.21.3-eks-1"}`,
+		"/api":     `{"kind":"APIVersions","versions":["v1"]}`,
+		"/apis":    `{"kind":"APIGroupList","apiVersion":"v1","groups":[]}`,
+		"/api/v1":  `{"kind":"APIResourceList","groupVersion":"v1","resources":[]}`,
+		"/apis/apiregistration.k8s.io/v1/apiservices": `{"kind":"APIServiceList","apiVersion":"apiregistration.k8s.io/v1","items":[]}`,
+	}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == dataJSON {
+			w.Write([]byte(mock.MockValidData)) //nolint: errcheck
+			return
+		}
+		response, ok := responses[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Add("Content-Type", "application/json")
+		w.Write([]byte(response)) //nolint: errcheck
+	}))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func TestContexts(t *testing.T) {
+	kubeconfig := writeKubeconfig(t, map[string]string{"prod": "https://prod", "dev": "https://dev"})
+
+	t.Run("all the contexts should be returned sorted", func(t *testing.T) {
+		pug := &Kubepug{Config: &Config{AllContexts: true, ConfigFlags: &genericclioptions.ConfigFlags{KubeConfig: ptr.To(kubeconfig)}}}
+		contexts, err := pug.contexts()
+		require.NoError(t, err)
+		require.Equal(t, []string{"dev", "prod"}, contexts)
+	})
+
+	t.Run("contexts should be returned as set", func(t *testing.T) {
+		pug := &Kubepug{Config: &Config{Contexts: []string{"prod"}}}
+		contexts, err := pug.contexts()
+		require.NoError(t, err)
+		require.Equal(t, []string{"prod"}, contexts)
+	})
+
+	t.Run("contexts cannot be used with file inputs", func(t *testing.T) {
+		pug := &Kubepug{Config: &Config{Contexts: []string{"prod"}, Input: "deployment.yaml"}}
+		_, err := pug.contexts()
+		require.ErrorContains(t, err, "contexts can only be used when scanning clusters")
+	})
+}
+
+func TestGetDeprecatedContexts(t *testing.T) {
+	ts := newClusterServer(t)
+
+	t.Run("results of each cluster should be summarized", func(t *testing.T) {
+		kubeconfig := writeKubeconfig(t, map[string]string{"prod": ts.URL, "broken": "http://127.0.0.1:1"})
+		pug := &Kubepug{Config: &Config{
+			GeneratedStore: ts.URL + dataJSON,
+			AllContexts:    true,
+			ConfigFlags:    &genericclioptions.ConfigFlags{KubeConfig: ptr.To(kubeconfig)},
+		}}
+
+		result, err := pug.GetDeprecated(context.Background())
+		require.NoError(t, err)
+		require.Len(t, result.Clusters, 2)
+		require.Equal(t, "broken", result.Clusters[0].Name)
+		require.NotEmpty(t, result.Clusters[0].Error)
+		require.Equal(t, results.ClusterSummary{Name: "prod"}, result.Clusters[1])
+	})
+
+	t.Run("an error should be returned when no cluster can be scanned", func(t *testing.T) {
+		kubeconfig := writeKubeconfig(t, map[string]string{"broken": "http://127.0.0.1:1"})
+		pug := &Kubepug{Config: &Config{
+			GeneratedStore: ts.URL + dataJSON,
+			Contexts:       []string{"broken"},
+			ConfigFlags:    &genericclioptions.ConfigFlags{KubeConfig: ptr.To(kubeconfig)},
+		}}
+
+		_, err := pug.GetDeprecated(context.Background())
+		require.ErrorContains(t, err, "cluster broken")
+	})
+}
+
+func TestGetPlan(t *testing.T) {
+	t.Run("a plan should be returned for the input", func(t *testing.T) {
+		pug := &Kubepug{Config: &Config{
+			GeneratedStore: "../test/testdata/plan/data.json",
+			K8sVersion:     "v1.27.0",
+			Input:          "../test/testdata/plan/manifests",
+		}}
+
+		plan, err := pug.GetPlan(context.Background(), "v1.25.0")
+		require.NoError(t, err)
+		require.Equal(t, "1.25", plan.Current)
+		require.Equal(t, "1.27", plan.Target)
+		require.Len(t, plan.Hops, 3)
+
+		migrateBy := make(map[string]string)
+		for _, migration := range plan.Checklist {
+			migrateBy[migration.Kind] = migration.MigrateBy
+		}
+		require.Equal(t, map[string]string{
+			"CronJob":                 "1.25",
+			"HorizontalPodAutoscaler": "1.25",
+			"CSIStorageCapacity":      "1.26",
+		}, migrateBy)
+	})
+
+	t.Run("deprecated custom resources should be on the plan", func(t *testing.T) {
+		pug := &Kubepug{Config: &Config{
+			GeneratedStore: "../test/testdata/plan/data.json",
+			K8sVersion:     "v1.27.0",
+			CRDFiles:       "../test/testdata/crds",
+			Input:          "../test/testdata/customresources",
+		}}
+
+		plan, err := pug.GetPlan(context.Background(), "v1.25.0")
+		require.NoError(t, err)
+		require.Len(t, plan.Hops[0].Deprecated, 1)
+		require.Equal(t, "Widget", plan.Hops[0].Deprecated[0].Kind)
+		require.Len(t, plan.Checklist, 1)
+		require.Equal(t, "v1beta1", plan.Checklist[0].Version)
+		require.Equal(t, "legacy-widget", plan.Checklist[0].Items[0].ObjectName)
+	})
+
+	t.Run("target version should be valid", func(t *testing.T) {
+		pug := &Kubepug{Config: &Config{GeneratedStore: "../test/testdata/plan/data.json", K8sVersion: "master"}}
+		_, err := pug.GetPlan(context.Background(), "v1.25.0")
+		require.ErrorContains(t, err, "should be a valid semantic version")
+	})
+}
+
+func TestGetFixes(t *testing.T) {
+	t.Run("the files using deprecated APIs should be fixed", func(t *testing.T) {
+		pug := &Kubepug{Config: &Config{
+			GeneratedStore: "../test/testdata/fix/data.json",
+			K8sVersion:     "v1.25.0",
+			Input:          "../test/testdata/fix/manifests",
+		}}
+
+		fixes, err := pug.GetFixes(context.Background())
+		require.NoError(t, err)
+
+		changed := make(map[string]bool)
+		for _, fix := range fixes {
+			changed[filepath.Base(fix.Location)] = fix.Changed()
+		}
+		require.Equal(t, map[string]bool{
+			"cronjob.yaml": true,
+			"ingress.json": true,
+			"ingress.yaml": true,
+			"list.yaml":    true,
+			"pdb.yaml":     true,
+			"psp.yaml":     false,
+		}, changed)
+	})
+
+	t.Run("APIs not deprecated on the target version should not be fixed", func(t *testing.T) {
+		pug := &Kubepug{Config: &Config{
+			GeneratedStore: "../test/testdata/fix/data.json",
+			K8sVersion:     "v1.20.0",
+			Input:          "../test/testdata/fix/manifests/cronjob.yaml",
+		}}
+
+		fixes, err := pug.GetFixes(context.Background())
+		require.NoError(t, err)
+		require.Empty(t, fixes)
+	})
+
+	t.Run("STDIN cannot be fixed", func(t *testing.T) {
+		pug := &Kubepug{Config: &Config{GeneratedStore: "../test/testdata/fix/data.json", Input: "-"}}
+		_, err := pug.GetFixes(context.Background())
+		require.ErrorContains(t, err, "a file or directory input is required")
+	})
+}
+
+func TestWithClusterVersion(t *testing.T) {
+	ts := newClusterServer(t)
+	kubeconfig := writeKubeconfig(t, map[string]string{"prod": ts.URL})
+
+	generated, err := generatedstore.NewGeneratedStore(context.Background(), generatedstore.StoreConfig{Path: "../test/testdata/plan/data.json"})
+	require.NoError(t, err)
+
+	availability := func(config *Config) *bool {
+		storer := (&Kubepug{Config: config}).withClusterVersion(generated)
+		status, err := storer.GetAPIDefinition(context.Background(), "batch", "v1beta1", "CronJob")
+		require.NoError(t, err)
+		return status.ReplacementAvailable
+	}
+
+	t.Run("cluster version should be used", func(t *testing.T) {
+		config := &Config{ConfigFlags: &genericclioptions.ConfigFlags{KubeConfig: ptr.To(kubeconfig), Context: ptr.To("prod")}}
+		require.Equal(t, ptr.To(true), availability(config))
+	})
+
+	t.Run("cluster version should not be detected for file inputs", func(t *testing.T) {
+		config := &Config{Input: "deployment.yaml", ConfigFlags: &genericclioptions.ConfigFlags{KubeConfig: ptr.To(kubeconfig), Context: ptr.To("prod")}}
+		require.Nil(t, availability(config))
+	})
+
+	t.Run("unreachable clusters should keep the store as is", func(t *testing.T) {
+		config := &Config{ConfigFlags: &genericclioptions.ConfigFlags{KubeConfig: ptr.To("/blabla123/kconfig")}}
+		require.Nil(t, availability(config))
+	})
+}
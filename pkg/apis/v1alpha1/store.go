@@ -38,4 +38,20 @@ type APIVersionStatus struct {
 	IntroducedVersion string `json:"introducedVersion,omitempty"`
 	// Replacement represents what is the proper replacement of this API
 	Replacement *GroupVersionKind `json:"replacement,omitempty"`
+	// ReplacementAvailable represents if the replacement is available on the Kubernetes version
+	// currently running. It is nil when this is unknown
+	ReplacementAvailable *bool `json:"replacementAvailable,omitempty"`
+}
+
+// FieldStatus represents a deprecated or deleted field of an API, as returned by a store query
+type FieldStatus struct {
+	// Path is the path of the field on the object. A "*" element matches any item of a list
+	// or any key of a map
+	Path []string `json:"path"`
+	// Description represents the description of the field
+	Description string `json:"description,omitempty"`
+	// DeprecationVersion represents when this field was marked as deprecated
+	DeprecationVersion string `json:"deprecationVersion,omitempty"`
+	// DeletedVersion represents when this field was removed
+	DeletedVersion string `json:"deletedVersion,omitempty"`
 }
@@ -0,0 +1,153 @@
+package fixer
+
+import (
+	"gopkg.in/yaml.v3"
+)
+
+const ingressClassAnnotation = "kubernetes.io/ingress.class"
+
+// conversion converts the fields of an object whose schema changed on the replacement API
+type conversion struct {
+	// to is the apiVersion the conversion was written for
+	to string
+	// convert changes the object fields, returning if any of them was changed and the
+	// fields that could not be converted automatically
+	convert func(obj *yaml.Node) (changed bool, warnings []string)
+}
+
+// conversions are the known conversions, indexed by the apiVersion/kind they convert from. The
+// objects of other APIs only get their apiVersion changed, and should be reviewed
+var conversions = map[string]conversion{
+	"extensions/v1beta1/Ingress":         {to: "networking.k8s.io/v1", convert: convertIngress},
+	"networking.k8s.io/v1beta1/Ingress":  {to: "networking.k8s.io/v1", convert: convertIngress},
+	"batch/v1beta1/CronJob":              {to: "batch/v1", convert: sameSchema},
+	"policy/v1beta1/PodDisruptionBudget": {to: "policy/v1", convert: convertPodDisruptionBudget},
+}
+
+// sameSchema is used by the APIs whose replacement has exactly the same fields
+func sameSchema(_ *yaml.Node) (changed bool, warnings []string) {
+	return false, nil
+}
+
+// convertIngress moves the backends to the networking.k8s.io/v1 format, where the service
+// fields are nested, spec.backend is named spec.defaultBackend and every path has a pathType
+func convertIngress(obj *yaml.Node) (changed bool, warnings []string) {
+	if nestedValue(obj, "metadata", "annotations", ingressClassAnnotation) != nil {
+		warnings = append(warnings, "the "+ingressClassAnnotation+" annotation is deprecated, spec.ingressClassName should reference an IngressClass instead")
+	}
+
+	spec := mappingValue(obj, "spec")
+	if i := mappingIndex(spec, "backend"); i >= 0 {
+		spec.Content[i].Value = "defaultBackend"
+		convertIngressBackend(spec.Content[i+1])
+		changed = true
+	}
+
+	rules := mappingValue(spec, "rules")
+	if rules == nil || rules.Kind != yaml.SequenceNode {
+		return changed, warnings
+	}
+	for _, rule := range rules.Content {
+		paths := nestedValue(rule, "http", "paths")
+		if paths == nil || paths.Kind != yaml.SequenceNode {
+			continue
+		}
+		for _, path := range paths.Content {
+			if path.Kind != yaml.MappingNode {
+				continue
+			}
+			if convertIngressBackend(mappingValue(path, "backend")) {
+				changed = true
+			}
+			if mappingIndex(path, "pathType") < 0 {
+				// ImplementationSpecific keeps the behavior of the paths without a type
+				at := len(path.Content)
+				if i := mappingIndex(path, "path"); i >= 0 {
+					at = i + 2
+				}
+				path.Content = append(path.Content[:at], append([]*yaml.Node{newScalar("pathType"), newScalar("ImplementationSpecific")}, path.Content[at:]...)...)
+				changed = true
+			}
+		}
+	}
+
+	return changed, warnings
+}
+
+// convertIngressBackend replaces the serviceName and servicePort fields of a backend by
+// the service field, keeping the comments of the first one of them
+func convertIngressBackend(backend *yaml.Node) bool {
+	nameIndex := mappingIndex(backend, "serviceName")
+	portIndex := mappingIndex(backend, "servicePort")
+	if nameIndex < 0 && portIndex < 0 {
+		return false
+	}
+
+	service := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	if nameIndex >= 0 {
+		service.Content = append(service.Content, newScalar("name"), backend.Content[nameIndex+1])
+	}
+	if portIndex >= 0 {
+		port := backend.Content[portIndex+1]
+		// servicePort is an IntOrString, which is either a port number or a port name
+		field := "name"
+		if port.ShortTag() == "!!int" {
+			field = "number"
+		}
+		service.Content = append(service.Content, newScalar("port"), &yaml.Node{
+			Kind:    yaml.MappingNode,
+			Tag:     "!!map",
+			Content: []*yaml.Node{newScalar(field), port},
+		})
+	}
+
+	content := make([]*yaml.Node, 0, len(backend.Content))
+	for i := 0; i+1 < len(backend.Content); i += 2 {
+		if i != nameIndex && i != portIndex {
+			content = append(content, backend.Content[i], backend.Content[i+1])
+			continue
+		}
+		if service == nil {
+			continue
+		}
+		key := newScalar("service")
+		key.HeadComment = backend.Content[i].HeadComment
+		key.LineComment = backend.Content[i].LineComment
+		content = append(content, key, service)
+		service = nil
+	}
+	backend.Content = content
+
+	return true
+}
+
+// convertPodDisruptionBudget does not change any field, as policy/v1 has the same fields of
+// policy/v1beta1. But an empty selector, that selects no pods on policy/v1beta1, selects every
+// pod of the namespace on policy/v1
+func convertPodDisruptionBudget(obj *yaml.Node) (changed bool, warnings []string) {
+	if isEmpty(nestedValue(obj, "spec", "selector")) {
+		warnings = append(warnings, "spec.selector is empty, which selects no pods on policy/v1beta1 but every pod of the namespace on policy/v1")
+	}
+	return false, warnings
+}
+
+// isEmpty returns if a node is missing, null, or only contains empty mappings and sequences
+func isEmpty(node *yaml.Node) bool {
+	if node == nil {
+		return true
+	}
+	switch node.Kind {
+	case yaml.ScalarNode:
+		return node.ShortTag() == "!!null"
+	case yaml.SequenceNode:
+		return len(node.Content) == 0
+	case yaml.MappingNode:
+		for i := 1; i < len(node.Content); i += 2 {
+			if !isEmpty(node.Content[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
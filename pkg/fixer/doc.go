@@ -0,0 +1,5 @@
+// Package fixer provide types and methods used to rewrite the manifests
+// using deprecated APIs to their replacements
+package fixer
+
+// import "github.com/kubepug/kubepug/pkg/fixer"
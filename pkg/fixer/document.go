@@ -0,0 +1,237 @@
+package fixer
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// document is a YAML document of a manifest file
+type document struct {
+	// separator is the "---" line preceding the document, if any
+	separator []byte
+	body      []byte
+}
+
+// splitDocuments splits a manifest file on its "---" lines, keeping the separators so
+// the file can be rebuilt exactly as it was
+func splitDocuments(manifests []byte) []document {
+	docs := []document{{}}
+	for _, line := range bytes.SplitAfter(manifests, []byte("\n")) {
+		if isSeparator(line) {
+			docs = append(docs, document{separator: line})
+			continue
+		}
+		docs[len(docs)-1].body = append(docs[len(docs)-1].body, line...)
+	}
+	return docs
+}
+
+func isSeparator(line []byte) bool {
+	if !bytes.HasPrefix(line, []byte("---")) {
+		return false
+	}
+	return len(line) == 3 || strings.ContainsRune(" \t\r\n", rune(line[3]))
+}
+
+// edit replaces the value of a scalar on the document text
+type edit struct {
+	line   int
+	column int
+	old    string
+	new    string
+}
+
+// setValue changes the value of a scalar node, returning the edit that does the same on the document text
+func setValue(node *yaml.Node, value string) edit {
+	e := edit{line: node.Line, column: node.Column, old: node.Value, new: value}
+	node.Value = value
+	return e
+}
+
+// applyEdits replaces the scalars on the document text, keeping the quotes used by each one of them.
+// ok is false when a scalar is not found where the parser reported it
+func applyEdits(body []byte, edits []edit) (fixed []byte, ok bool) {
+	lines := strings.SplitAfter(string(body), "\n")
+
+	// Edits are applied from the end, so the columns of the remaining ones are still valid
+	sort.Slice(edits, func(i, j int) bool {
+		if edits[i].line != edits[j].line {
+			return edits[i].line > edits[j].line
+		}
+		return edits[i].column > edits[j].column
+	})
+
+	for _, e := range edits {
+		if e.line < 1 || e.line > len(lines) {
+			return nil, false
+		}
+		line := []rune(lines[e.line-1])
+		start := e.column - 1
+		old := []rune(e.old)
+		if start < 0 || start >= len(line) {
+			return nil, false
+		}
+
+		if quote := line[start]; quote == '"' || quote == '\'' {
+			start++
+			if start+len(old) >= len(line) || line[start+len(old)] != quote {
+				return nil, false
+			}
+		}
+		if start+len(old) > len(line) || string(line[start:start+len(old)]) != e.old {
+			return nil, false
+		}
+
+		lines[e.line-1] = string(line[:start]) + e.new + string(line[start+len(old):])
+	}
+
+	return []byte(strings.Join(lines, "")), true
+}
+
+// encodeDocument encodes a document again, in the same format (YAML or JSON) and with the same
+// indentation of the original body. The trailing new lines of the body are kept
+func encodeDocument(body []byte, root *yaml.Node) ([]byte, error) {
+	trimmed := bytes.TrimRight(body, "\n")
+	trailing := body[len(trimmed):]
+	indent := detectIndent(body)
+
+	var encoded bytes.Buffer
+	if bytes.HasPrefix(bytes.TrimSpace(body), []byte("{")) {
+		if err := writeJSON(&encoded, root, "", strings.Repeat(" ", indent)); err != nil {
+			return nil, err
+		}
+	} else {
+		encoder := yaml.NewEncoder(&encoded)
+		encoder.SetIndent(indent)
+		if err := encoder.Encode(root); err != nil {
+			return nil, err
+		}
+		if err := encoder.Close(); err != nil {
+			return nil, err
+		}
+	}
+
+	return append(bytes.TrimRight(encoded.Bytes(), "\n"), trailing...), nil
+}
+
+// detectIndent returns the indentation of the first indented line of the document, or 2
+func detectIndent(body []byte) int {
+	for _, line := range strings.Split(string(body), "\n") {
+		content := strings.TrimLeft(line, " ")
+		if content == "" || strings.HasPrefix(content, "#") {
+			continue
+		}
+		if indent := len(line) - len(content); indent > 0 {
+			return indent
+		}
+	}
+	return 2
+}
+
+// writeJSON encodes a node as indented JSON, keeping the order of the keys
+func writeJSON(buf *bytes.Buffer, node *yaml.Node, prefix, indent string) error {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			return nil
+		}
+		return writeJSON(buf, node.Content[0], prefix, indent)
+	case yaml.AliasNode:
+		return writeJSON(buf, node.Alias, prefix, indent)
+	case yaml.MappingNode:
+		if len(node.Content) == 0 {
+			buf.WriteString("{}")
+			return nil
+		}
+		buf.WriteString("{\n")
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, err := json.Marshal(node.Content[i].Value)
+			if err != nil {
+				return err
+			}
+			buf.WriteString(prefix + indent)
+			buf.Write(key)
+			buf.WriteString(": ")
+			if err := writeJSON(buf, node.Content[i+1], prefix+indent, indent); err != nil {
+				return err
+			}
+			if i+2 < len(node.Content) {
+				buf.WriteString(",")
+			}
+			buf.WriteString("\n")
+		}
+		buf.WriteString(prefix + "}")
+	case yaml.SequenceNode:
+		if len(node.Content) == 0 {
+			buf.WriteString("[]")
+			return nil
+		}
+		buf.WriteString("[\n")
+		for i, item := range node.Content {
+			buf.WriteString(prefix + indent)
+			if err := writeJSON(buf, item, prefix+indent, indent); err != nil {
+				return err
+			}
+			if i+1 < len(node.Content) {
+				buf.WriteString(",")
+			}
+			buf.WriteString("\n")
+		}
+		buf.WriteString(prefix + "]")
+	case yaml.ScalarNode:
+		switch node.ShortTag() {
+		case "!!null":
+			buf.WriteString("null")
+		case "!!bool", "!!int", "!!float":
+			buf.WriteString(node.Value)
+		default:
+			value, err := json.Marshal(node.Value)
+			if err != nil {
+				return err
+			}
+			buf.Write(value)
+		}
+	}
+	return nil
+}
+
+// mappingIndex returns the position of a key on the content of a mapping, or -1
+func mappingIndex(node *yaml.Node, key string) int {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return -1
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// mappingValue returns the value of a key of a mapping, or nil
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	i := mappingIndex(node, key)
+	if i < 0 {
+		return nil
+	}
+	return node.Content[i+1]
+}
+
+// nestedValue returns the value of nested mappings keys, like metadata.name, or nil
+func nestedValue(node *yaml.Node, keys ...string) *yaml.Node {
+	for _, key := range keys {
+		node = mappingValue(node, key)
+		if node == nil {
+			return nil
+		}
+	}
+	return node
+}
+
+func newScalar(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+}
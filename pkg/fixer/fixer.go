@@ -0,0 +1,264 @@
+package fixer
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"gopkg.in/yaml.v3"
+
+	api "github.com/kubepug/kubepug/pkg/apis/v1alpha1"
+	"github.com/kubepug/kubepug/pkg/results"
+)
+
+// Object is an object found on a manifest using a deprecated API
+type Object struct {
+	APIVersion string
+	Kind       string
+	Name       string
+	Namespace  string
+	// Replacement is the apiVersion the object was converted to. It is empty when
+	// the object could not be converted
+	Replacement string
+	// Warnings contains the fields that could not be converted automatically, and
+	// should be reviewed before applying the object
+	Warnings []string
+}
+
+// FileFix contains the result of fixing a manifest file
+type FileFix struct {
+	Location string
+	Original []byte
+	Fixed    []byte
+	Objects  []Object
+}
+
+// Changed returns if any object of the file was rewritten
+func (f *FileFix) Changed() bool {
+	return !bytes.Equal(f.Original, f.Fixed)
+}
+
+// Diff returns the unified diff between the original and the fixed file
+func (f *FileFix) Diff() (string, error) {
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(f.Original)),
+		B:        difflib.SplitLines(string(f.Fixed)),
+		FromFile: f.Location,
+		ToFile:   f.Location,
+		Context:  3,
+	})
+}
+
+// Write replaces the original file with the fixed one, keeping its permissions
+func (f *FileFix) Write() error {
+	info, err := os.Stat(f.Location)
+	if err != nil {
+		return fmt.Errorf("failed to write manifest file %s: %w", f.Location, err)
+	}
+	if err := os.WriteFile(f.Location, f.Fixed, info.Mode().Perm()); err != nil {
+		return fmt.Errorf("failed to write manifest file %s: %w", f.Location, err)
+	}
+	return nil
+}
+
+// Fixer rewrites the manifests using deprecated APIs to their replacements
+type Fixer struct {
+	// apis contains the deprecated APIs, indexed by apiVersion/kind
+	apis      map[string]results.ResultItem
+	locations []string
+}
+
+// NewFixer returns a Fixer for the deprecated APIs found on the manifests, as returned by FileInput
+func NewFixer(items ...results.ResultItem) *Fixer {
+	f := &Fixer{apis: make(map[string]results.ResultItem)}
+	seen := make(map[string]struct{})
+	for i := range items {
+		// Deprecated fields are not rewritten, only their APIs
+		if items[i].Field != "" {
+			continue
+		}
+		f.apis[apiVersion(items[i].Group, items[i].Version)+"/"+items[i].Kind] = items[i]
+		for _, item := range items[i].Items {
+			if _, ok := seen[item.Location]; ok || item.Location == "" {
+				continue
+			}
+			seen[item.Location] = struct{}{}
+			f.locations = append(f.locations, item.Location)
+		}
+	}
+	sort.Strings(f.locations)
+	return f
+}
+
+// Fix rewrites every file where a deprecated API was found. The files are not written,
+// FileFix.Write should be used for that
+func (f *Fixer) Fix() ([]*FileFix, error) {
+	fixes := make([]*FileFix, 0, len(f.locations))
+	for _, location := range f.locations {
+		fix, err := f.FixFile(location)
+		if err != nil {
+			return nil, err
+		}
+		fixes = append(fixes, fix)
+	}
+	return fixes, nil
+}
+
+// FixFile rewrites the manifests of a file
+func (f *Fixer) FixFile(location string) (*FileFix, error) {
+	original, err := os.ReadFile(location)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest file %s: %w", location, err)
+	}
+
+	fixed, objects := f.FixManifests(original)
+	return &FileFix{
+		Location: location,
+		Original: original,
+		Fixed:    fixed,
+		Objects:  objects,
+	}, nil
+}
+
+// FixManifests rewrites a set of YAML or JSON manifests, separated by "---". The documents
+// that cannot be parsed or that don't use a deprecated API are kept untouched
+func (f *Fixer) FixManifests(manifests []byte) ([]byte, []Object) {
+	var fixed bytes.Buffer
+	var objects []Object
+	for _, doc := range splitDocuments(manifests) {
+		body, docObjects := f.fixDocument(doc.body)
+		fixed.Write(doc.separator)
+		fixed.Write(body)
+		objects = append(objects, docObjects...)
+	}
+	return fixed.Bytes(), objects
+}
+
+// fixDocument rewrites the objects of a single document. When only the apiVersion and kind
+// change, the document text is edited in place so its formatting is kept. Otherwise the
+// document is encoded again, keeping its comments
+func (f *Fixer) fixDocument(body []byte) ([]byte, []Object) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(body, &root); err != nil || len(root.Content) == 0 {
+		return body, nil
+	}
+
+	var objects []Object
+	var edits []edit
+	var structural bool
+	for _, obj := range objectNodes(root.Content[0]) {
+		object, objEdits, changed, ok := f.fixObject(obj)
+		if !ok {
+			continue
+		}
+		objects = append(objects, object)
+		edits = append(edits, objEdits...)
+		structural = structural || changed
+	}
+
+	if len(edits) == 0 {
+		return body, objects
+	}
+
+	if !structural {
+		if fixed, ok := applyEdits(body, edits); ok {
+			return fixed, objects
+		}
+	}
+
+	fixed, err := encodeDocument(body, &root)
+	if err != nil {
+		for i := range objects {
+			objects[i].Replacement = ""
+			objects[i].Warnings = append(objects[i].Warnings, fmt.Sprintf("the document could not be rewritten: %s", err))
+		}
+		return body, objects
+	}
+	return fixed, objects
+}
+
+// fixObject converts an object to the replacement of its API, returning the text edits
+// of its apiVersion and kind, and if other fields of the object were changed. ok is false
+// when the object does not use a deprecated API
+func (f *Fixer) fixObject(obj *yaml.Node) (object Object, edits []edit, changed, ok bool) {
+	versionNode := mappingValue(obj, "apiVersion")
+	kindNode := mappingValue(obj, "kind")
+	if versionNode == nil || kindNode == nil {
+		return object, nil, false, false
+	}
+
+	key := versionNode.Value + "/" + kindNode.Value
+	deprecated, ok := f.apis[key]
+	if !ok {
+		return object, nil, false, false
+	}
+
+	object = Object{
+		APIVersion: versionNode.Value,
+		Kind:       kindNode.Value,
+	}
+	if name := nestedValue(obj, "metadata", "name"); name != nil {
+		object.Name = name.Value
+	}
+	if namespace := nestedValue(obj, "metadata", "namespace"); namespace != nil {
+		object.Namespace = namespace.Value
+	}
+
+	if deprecated.Replacement == nil {
+		object.Warnings = append(object.Warnings, fmt.Sprintf("%s %s has no replacement and should be migrated manually", object.APIVersion, object.Kind))
+		return object, nil, false, true
+	}
+
+	replacement := apiVersion(deprecated.Replacement.Group, deprecated.Replacement.Version)
+	if deprecated.ReplacementAvailable != nil && !*deprecated.ReplacementAvailable {
+		object.Warnings = append(object.Warnings, fmt.Sprintf("%s %s is not available on the current Kubernetes version, the object should only be applied after the upgrade", replacement, deprecated.Replacement.Kind))
+	}
+
+	conv, known := conversions[key]
+	if known && conv.to == replacement && deprecated.Replacement.Kind == kindNode.Value {
+		var warnings []string
+		changed, warnings = conv.convert(obj)
+		object.Warnings = append(object.Warnings, warnings...)
+	} else {
+		object.Warnings = append(object.Warnings, fmt.Sprintf("the fields were not verified against %s %s and should be reviewed", replacement, deprecated.Replacement.Kind))
+	}
+
+	edits = append(edits, setValue(versionNode, replacement))
+	if deprecated.Replacement.Kind != "" && deprecated.Replacement.Kind != kindNode.Value {
+		edits = append(edits, setValue(kindNode, deprecated.Replacement.Kind))
+	}
+	object.Replacement = replacement
+
+	return object, edits, changed, true
+}
+
+// objectNodes returns the objects of a document, which may be a single object or a List
+func objectNodes(node *yaml.Node) []*yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	kind := mappingValue(node, "kind")
+	items := mappingValue(node, "items")
+	if kind == nil || !strings.HasSuffix(kind.Value, "List") || items == nil || items.Kind != yaml.SequenceNode {
+		return []*yaml.Node{node}
+	}
+
+	objects := make([]*yaml.Node, 0, len(items.Content))
+	for _, item := range items.Content {
+		if item.Kind == yaml.MappingNode {
+			objects = append(objects, item)
+		}
+	}
+	return objects
+}
+
+// apiVersion returns the apiVersion of a group and version, which is the version only for the core group
+func apiVersion(group, version string) string {
+	if group == "" || group == api.CoreAPI {
+		return version
+	}
+	return group + "/" + version
+}
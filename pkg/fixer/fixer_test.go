@@ -0,0 +1,160 @@
+package fixer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/ptr"
+
+	api "github.com/kubepug/kubepug/pkg/apis/v1alpha1"
+	"github.com/kubepug/kubepug/pkg/results"
+)
+
+const (
+	manifestsDir = "../../test/testdata/fix/manifests"
+	expectedDir  = "../../test/testdata/fix/expected"
+)
+
+func deprecatedItem(group, version, kind string, replacement *api.GroupVersionKind) results.ResultItem {
+	return results.ResultItem{Group: group, Version: version, Kind: kind, Replacement: replacement}
+}
+
+func deprecatedAPIs() []results.ResultItem {
+	return []results.ResultItem{
+		deprecatedItem("extensions", "v1beta1", "Ingress", &api.GroupVersionKind{Group: "networking.k8s.io", Version: "v1", Kind: "Ingress"}),
+		deprecatedItem("networking.k8s.io", "v1beta1", "Ingress", &api.GroupVersionKind{Group: "networking.k8s.io", Version: "v1", Kind: "Ingress"}),
+		deprecatedItem("batch", "v1beta1", "CronJob", &api.GroupVersionKind{Group: "batch", Version: "v1", Kind: "CronJob"}),
+		deprecatedItem("policy", "v1beta1", "PodDisruptionBudget", &api.GroupVersionKind{Group: "policy", Version: "v1", Kind: "PodDisruptionBudget"}),
+		deprecatedItem("policy", "v1beta1", "PodSecurityPolicy", nil),
+		deprecatedItem("autoscaling", "v2beta2", "HorizontalPodAutoscaler", &api.GroupVersionKind{Group: "autoscaling", Version: "v2", Kind: "HorizontalPodAutoscaler"}),
+	}
+}
+
+func TestFixFile(t *testing.T) {
+	tests := []struct {
+		file     string
+		changed  bool
+		warnings map[string][]string
+	}{
+		{
+			file:     "cronjob.yaml",
+			changed:  true,
+			warnings: map[string][]string{"cleanup": nil},
+		},
+		{
+			file:    "ingress.yaml",
+			changed: true,
+			warnings: map[string][]string{
+				"web": {"the kubernetes.io/ingress.class annotation is deprecated, spec.ingressClassName should reference an IngressClass instead"},
+			},
+		},
+		{
+			file:     "ingress.json",
+			changed:  true,
+			warnings: map[string][]string{"api": nil},
+		},
+		{
+			file:    "pdb.yaml",
+			changed: true,
+			warnings: map[string][]string{
+				"web":     nil,
+				"nothing": {"spec.selector is empty, which selects no pods on policy/v1beta1 but every pod of the namespace on policy/v1"},
+			},
+		},
+		{
+			file:    "psp.yaml",
+			changed: false,
+			warnings: map[string][]string{
+				"restricted": {"policy/v1beta1 PodSecurityPolicy has no replacement and should be migrated manually"},
+			},
+		},
+		{
+			file:    "list.yaml",
+			changed: true,
+			warnings: map[string][]string{
+				"web": {"the fields were not verified against autoscaling/v2 HorizontalPodAutoscaler and should be reviewed"},
+			},
+		},
+	}
+
+	fixer := NewFixer(deprecatedAPIs()...)
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			fix, err := fixer.FixFile(filepath.Join(manifestsDir, tt.file))
+			require.NoError(t, err)
+
+			expected, err := os.ReadFile(filepath.Join(expectedDir, tt.file))
+			require.NoError(t, err)
+			require.Equal(t, string(expected), string(fix.Fixed))
+			require.Equal(t, tt.changed, fix.Changed())
+
+			warnings := make(map[string][]string)
+			for _, object := range fix.Objects {
+				warnings[object.Name] = object.Warnings
+			}
+			require.Equal(t, tt.warnings, warnings)
+		})
+	}
+}
+
+func TestFixManifests(t *testing.T) {
+	cronjob := deprecatedItem("batch", "v1beta1", "CronJob", &api.GroupVersionKind{Group: "batch", Version: "v1", Kind: "CronJob"})
+
+	t.Run("documents without deprecated APIs should be kept untouched", func(t *testing.T) {
+		manifests := "---\n# only a comment\n---\nnot: [valid\n---\napiVersion: v1\nkind: ConfigMap\n"
+		fixed, objects := NewFixer(cronjob).FixManifests([]byte(manifests))
+		require.Equal(t, manifests, string(fixed))
+		require.Empty(t, objects)
+	})
+
+	t.Run("flow mappings should be edited in place", func(t *testing.T) {
+		fixed, objects := NewFixer(cronjob).FixManifests([]byte("{apiVersion: 'batch/v1beta1', kind: CronJob, metadata: {name: a}}\n"))
+		require.Equal(t, "{apiVersion: 'batch/v1', kind: CronJob, metadata: {name: a}}\n", string(fixed))
+		require.Len(t, objects, 1)
+	})
+
+	t.Run("unavailable replacements should be flagged", func(t *testing.T) {
+		unavailable := cronjob
+		unavailable.ReplacementAvailable = ptr.To(false)
+		fixed, objects := NewFixer(unavailable).FixManifests([]byte("apiVersion: batch/v1beta1\nkind: CronJob\n"))
+		require.Equal(t, "apiVersion: batch/v1\nkind: CronJob\n", string(fixed))
+		require.Equal(t, []string{"batch/v1 CronJob is not available on the current Kubernetes version, the object should only be applied after the upgrade"}, objects[0].Warnings)
+	})
+
+	t.Run("replacements of other kinds should change the kind", func(t *testing.T) {
+		replaced := deprecatedItem("", "v1", "ComponentStatus", &api.GroupVersionKind{Group: "health.example.com", Version: "v1", Kind: "Health"})
+		fixed, objects := NewFixer(replaced).FixManifests([]byte("apiVersion: v1\nkind: ComponentStatus\n"))
+		require.Equal(t, "apiVersion: health.example.com/v1\nkind: Health\n", string(fixed))
+		require.Equal(t, "health.example.com/v1", objects[0].Replacement)
+	})
+}
+
+func TestFileFix(t *testing.T) {
+	location := filepath.Join(t.TempDir(), "cronjob.yaml")
+	require.NoError(t, os.WriteFile(location, []byte("apiVersion: batch/v1beta1\nkind: CronJob\n"), 0o600))
+
+	fixes, err := NewFixer(results.ResultItem{
+		Group:       "batch",
+		Version:     "v1beta1",
+		Kind:        "CronJob",
+		Replacement: &api.GroupVersionKind{Group: "batch", Version: "v1", Kind: "CronJob"},
+		Items:       []results.Item{{Location: location}, {Location: location}},
+	}).Fix()
+	require.NoError(t, err)
+	require.Len(t, fixes, 1)
+
+	diff, err := fixes[0].Diff()
+	require.NoError(t, err)
+	require.Contains(t, diff, "-apiVersion: batch/v1beta1\n+apiVersion: batch/v1\n")
+
+	require.NoError(t, fixes[0].Write())
+	written, err := os.ReadFile(location)
+	require.NoError(t, err)
+	require.Equal(t, "apiVersion: batch/v1\nkind: CronJob\n", string(written))
+
+	info, err := os.Stat(location)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+}
@@ -0,0 +1,31 @@
+package formatter
+
+import (
+	"bytes"
+	csvencoding "encoding/csv"
+
+	"github.com/kubepug/kubepug/pkg/results"
+)
+
+type csv struct {
+	rowsConfig
+}
+
+func newCSVFormatter(config rowsConfig) Formatter {
+	return &csv{
+		rowsConfig: config,
+	}
+}
+
+// Output writes a CSV row for each object affected by a deleted or deprecated API, after a header row
+func (f *csv) Output(data results.Result) ([]byte, error) {
+	var out bytes.Buffer
+	w := csvencoding.NewWriter(&out)
+	if err := w.Write(f.columns); err != nil {
+		return nil, err
+	}
+	if err := w.WriteAll(f.rows(&data)); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
@@ -0,0 +1,71 @@
+package formatter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCSVOutput(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  Config
+		want    string
+		wantErr string
+	}{
+		{
+			name: "default columns should be used",
+			want: "STATUS,GROUP,VERSION,KIND,NAMESPACE,NAME,LOCATION,REPLACEMENT,K8S-VERSION\n" +
+				"deleted,extensions,v1beta1,Ingress,apps,web,,networking.k8s.io/v1/Ingress,1.22\n" +
+				"deleted,extensions,v1beta1,Ingress,,alice,,networking.k8s.io/v1/Ingress,1.22\n" +
+				"deprecated,batch,v1beta1,CronJob,jobs,cleanup,manifests/cronjob.yaml:3:1,batch/v1/CronJob,1.21\n",
+		},
+		{
+			name:   "selected columns should be used",
+			config: Config{Columns: []string{"kind", "name", "scope"}},
+			want:   "KIND,NAME,SCOPE\nIngress,web,OBJECT\nIngress,alice,CALLER\nCronJob,cleanup,OBJECT\n",
+		},
+		{
+			name:   "rows should be sorted",
+			config: Config{Columns: []string{"name", "k8s-version"}, SortBy: "NAME"},
+			want:   "NAME,K8S-VERSION\nalice,1.22\ncleanup,1.21\nweb,1.22\n",
+		},
+		{
+			name:   "versions should be sorted by their numbers",
+			config: Config{Columns: []string{"name"}, SortBy: "k8s-version"},
+			want:   "NAME\ncleanup\nweb\nalice\n",
+		},
+		{
+			name:    "invalid columns should fail",
+			config:  Config{Columns: []string{"kind", "color"}},
+			wantErr: "invalid column COLOR",
+		},
+		{
+			name:    "invalid sort columns should fail",
+			config:  Config{SortBy: "color"},
+			wantErr: "invalid sort column color",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := NewFormatterWithConfig("csv", tt.config)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			out, err := f.Output(junitResult)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, string(out))
+		})
+	}
+}
+
+func TestLessVersion(t *testing.T) {
+	require.True(t, lessVersion("1.9", "1.22"))
+	require.False(t, lessVersion("1.22", "1.9"))
+	require.True(t, lessVersion("1.22", "2.0"))
+	require.True(t, lessVersion("", "1.9"))
+	require.False(t, lessVersion("1.22", "1.22"))
+}
@@ -2,6 +2,8 @@ package formatter
 
 import (
 	"fmt"
+	"path/filepath"
+	"strings"
 
 	"github.com/kubepug/kubepug/pkg/results"
 )
@@ -20,9 +22,29 @@ func NewFormatter(t string) Formatter {
 	return f
 }
 
+// Config defines the options of the formatters. Each option is used only by the formats that support it
+type Config struct {
+	// SkipDeprecated reports the deprecated APIs as skipped test cases instead of failures on the junit format
+	SkipDeprecated bool
+	// Template is the Go template, or the location of a file containing it, used by the template format
+	Template string
+	// Columns are the columns of the csv and table formats, defaulting to STATUS, GROUP, VERSION, KIND,
+	// NAMESPACE, NAME, LOCATION, REPLACEMENT and K8S-VERSION
+	Columns []string
+	// SortBy is the column the rows of the csv and table formats are sorted by. By default, the deleted
+	// APIs are listed before the deprecated ones
+	SortBy string
+}
+
 // NewFormatterWithError returns a formatter or an error that can be returned by the
 // formatter instance or in case the formatter is invalid
 func NewFormatterWithError(t string) (Formatter, error) {
+	return NewFormatterWithConfig(t, Config{})
+}
+
+// NewFormatterWithConfig returns a formatter configured by config, or an error in case
+// the formatter is invalid
+func NewFormatterWithConfig(t string, config Config) (Formatter, error) {
 	switch t {
 	case "stdout":
 		return newSTDOUTFormatter(false), nil
@@ -32,7 +54,109 @@ func NewFormatterWithError(t string) (Formatter, error) {
 		return newJSONFormatter(), nil
 	case "yaml":
 		return newYamlFormatter(), nil
+	case "sarif":
+		return newSARIFFormatter(), nil
+	case "junit":
+		return newJUnitFormatter(config.SkipDeprecated), nil
+	case "markdown":
+		return newMarkdownFormatter(), nil
+	case "html":
+		return newHTMLFormatter(), nil
+	case "csv", "table":
+		rows, err := newRowsConfig(config.Columns, config.SortBy)
+		if err != nil {
+			return nil, err
+		}
+		if t == "csv" {
+			return newCSVFormatter(rows), nil
+		}
+		return newTableFormatter(rows), nil
+	case "github":
+		return newGitHubFormatter(), nil
+	case "gitlab":
+		return newGitLabFormatter(), nil
+	case "template":
+		return newTemplateFormatter(config.Template)
 	default:
 		return nil, fmt.Errorf("invalid formatter selected: %s", t)
 	}
 }
+
+// resultID identifies the API of a result, like extensions/v1beta1/Ingress, appending the
+// field for deprecated fields
+func resultID(api *results.ResultItem) string {
+	id := apiName(api)
+	if api.Kind != "" {
+		id += "/" + api.Kind
+	}
+	if api.Field != "" {
+		id += "/" + api.Field
+	}
+	return id
+}
+
+// apiName returns the apiVersion of a result, like apps/v1 or just v1 for core APIs
+func apiName(api *results.ResultItem) string {
+	if api.Group == "" {
+		return api.Version
+	}
+	return api.Group + "/" + api.Version
+}
+
+// subject describes what is deprecated on a result, like "extensions/v1beta1 Ingress" or
+// "field spec.serviceAccount of v1 Pod"
+func subject(api *results.ResultItem) string {
+	if api.Field != "" {
+		return fmt.Sprintf("field %s of %s %s", api.Field, apiName(api), api.Kind)
+	}
+	if api.Kind == "" {
+		return apiName(api)
+	}
+	return fmt.Sprintf("%s %s", apiName(api), api.Kind)
+}
+
+// summary describes the status of a result, like "extensions/v1beta1 Ingress is deleted since Kubernetes 1.22"
+func summary(api *results.ResultItem, status string) string {
+	text := fmt.Sprintf("%s is %s", subject(api), status)
+	if api.K8sVersion != "" && api.K8sVersion != "unknown" {
+		text = fmt.Sprintf("%s since Kubernetes %s", text, api.K8sVersion)
+	}
+	return text
+}
+
+// details describes a result with its summary, description and replacement, one per line
+func details(api *results.ResultItem, status string) string {
+	lines := []string{summary(api, status)}
+	if api.Description != "" {
+		lines = append(lines, api.Description)
+	}
+	if api.Replacement != nil {
+		lines = append(lines, fmt.Sprintf("Replacement: %s/%s/%s", api.Replacement.Group, api.Replacement.Version, api.Replacement.Kind))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// itemMessage describes an item using a deleted or deprecated API, and where it should be migrated to
+func itemMessage(api *results.ResultItem, item *results.Item, status string) string {
+	who, verb := api.Kind+" "+item.ObjectName, "uses"
+	if item.Namespace != "" {
+		who = api.Kind + " " + item.Namespace + "/" + item.ObjectName
+	}
+	switch item.Scope {
+	case "CALLER":
+		who = "User " + item.User
+	case "REQUESTED":
+		who, verb = "Requests to "+item.ObjectName, "use"
+	}
+
+	message := fmt.Sprintf("%s %s %s, which is %s", who, verb, subject(api), status)
+	if api.Replacement != nil {
+		message = fmt.Sprintf("%s. It should be migrated to %s/%s/%s", message, api.Replacement.Group, api.Replacement.Version, api.Replacement.Kind)
+	}
+	return message
+}
+
+// filePath normalizes the location of a file, using forward slashes and no leading ./
+func filePath(location string) string {
+	return strings.TrimPrefix(filepath.ToSlash(location), "./")
+}
@@ -77,6 +77,60 @@ func TestNewFormatterWithError(t *testing.T) {
 			want:          &yaml{},
 			wantErr:       false,
 		},
+		{
+			name:          "sarif is valid",
+			formattertype: "sarif",
+			want:          &sarif{},
+			wantErr:       false,
+		},
+		{
+			name:          "junit is valid",
+			formattertype: "junit",
+			want:          &junit{},
+			wantErr:       false,
+		},
+		{
+			name:          "markdown is valid",
+			formattertype: "markdown",
+			want:          &markdown{},
+			wantErr:       false,
+		},
+		{
+			name:          "html is valid",
+			formattertype: "html",
+			want:          &html{},
+			wantErr:       false,
+		},
+		{
+			name:          "csv is valid",
+			formattertype: "csv",
+			want:          &csv{rowsConfig{columns: defaultColumns}},
+			wantErr:       false,
+		},
+		{
+			name:          "table is valid",
+			formattertype: "table",
+			want:          &table{rowsConfig{columns: defaultColumns}},
+			wantErr:       false,
+		},
+		{
+			name:          "github is valid",
+			formattertype: "github",
+			want:          &github{},
+			wantErr:       false,
+		},
+		{
+			name:          "gitlab is valid",
+			formattertype: "gitlab",
+			want:          &gitlab{},
+			wantErr:       false,
+		},
+		{
+			name:          "template without a template is an error",
+			formattertype: "template",
+			want:          nil,
+			wantErr:       true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -0,0 +1,52 @@
+package formatter
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/kubepug/kubepug/pkg/results"
+)
+
+type github struct{}
+
+func newGitHubFormatter() Formatter {
+	return &github{}
+}
+
+// Output writes a GitHub Actions workflow command for each object using a deleted (error) or deprecated
+// (warning) API. Objects read from files are annotated on the file and line, so they are shown inline on
+// pull requests. Other objects, like the ones of Helm releases or clusters, are annotated on the workflow run. See https://docs.github.com/en/actions/using-workflows/workflow-commands-for-github-actions
+func (f *github) Output(data results.Result) ([]byte, error) {
+	s := sliceBuilder{}
+	add := func(apis []results.ResultItem, command, status string) {
+		for i := range apis {
+			api := &apis[i]
+			for _, item := range api.Items {
+				var properties []string
+				if item.File {
+					properties = append(properties, "file="+escapeGitHubProperty(filePath(item.Location)))
+					if item.Line > 0 {
+						properties = append(properties, "line="+strconv.Itoa(item.Line), "col="+strconv.Itoa(item.Column))
+					}
+				}
+				properties = append(properties, "title="+escapeGitHubProperty(summary(api, status)))
+
+				s.add("::", command, " ", strings.Join(properties, ","), "::", escapeGitHubData(itemMessage(api, &item, status)), "\n")
+			}
+		}
+	}
+	add(data.DeletedAPIs, "error", "deleted")
+	add(data.DeprecatedAPIs, "warning", "deprecated")
+
+	return []byte(s.String()), nil
+}
+
+// escapeGitHubData escapes the message of a workflow command
+func escapeGitHubData(data string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(data)
+}
+
+// escapeGitHubProperty escapes the value of a workflow command property
+func escapeGitHubProperty(property string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(property)
+}
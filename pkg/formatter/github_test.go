@@ -0,0 +1,53 @@
+package formatter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	apis "github.com/kubepug/kubepug/pkg/apis/v1alpha1"
+	"github.com/kubepug/kubepug/pkg/results"
+)
+
+func TestGitHubOutput(t *testing.T) {
+	f := &github{}
+
+	out, err := f.Output(results.Result{
+		DeprecatedAPIs: []results.ResultItem{
+			{
+				Group: "", Version: "v1", Kind: "Pod", Field: "spec.serviceAccount", K8sVersion: "1.1",
+				Items: []results.Item{{Scope: "OBJECT", ObjectName: "debug", Namespace: "default"}},
+			},
+		},
+		DeletedAPIs: []results.ResultItem{
+			{
+				Group: "extensions", Version: "v1beta1", Kind: "Ingress", K8sVersion: "1.22",
+				Replacement: &apis.GroupVersionKind{Group: "networking.k8s.io", Version: "v1", Kind: "Ingress"},
+				Items: []results.Item{
+					{Scope: "OBJECT", ObjectName: "web", Namespace: "apps", Location: "./manifests/ingress.yaml", Line: 12, Column: 1, File: true},
+					{Scope: "OBJECT", ObjectName: "api", Namespace: "apps", Location: "manifests/a,b.yaml", File: true},
+					{Scope: "OBJECT", ObjectName: "app", Namespace: "default", Location: "helm:default/app@3"},
+					results.RequestedItem("ingresses", "apiserver:/metrics"),
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t,
+		"::error file=manifests/ingress.yaml,line=12,col=1,title=extensions/v1beta1 Ingress is deleted since Kubernetes 1.22::"+
+			"Ingress apps/web uses extensions/v1beta1 Ingress, which is deleted. It should be migrated to networking.k8s.io/v1/Ingress\n"+
+			"::error file=manifests/a%2Cb.yaml,title=extensions/v1beta1 Ingress is deleted since Kubernetes 1.22::"+
+			"Ingress apps/api uses extensions/v1beta1 Ingress, which is deleted. It should be migrated to networking.k8s.io/v1/Ingress\n"+
+			"::error title=extensions/v1beta1 Ingress is deleted since Kubernetes 1.22::"+
+			"Ingress default/app uses extensions/v1beta1 Ingress, which is deleted. It should be migrated to networking.k8s.io/v1/Ingress\n"+
+			"::error title=extensions/v1beta1 Ingress is deleted since Kubernetes 1.22::"+
+			"Requests to ingresses use extensions/v1beta1 Ingress, which is deleted. It should be migrated to networking.k8s.io/v1/Ingress\n"+
+			"::warning title=field spec.serviceAccount of v1 Pod is deprecated since Kubernetes 1.1::"+
+			"Pod default/debug uses field spec.serviceAccount of v1 Pod, which is deprecated\n",
+		string(out))
+}
+
+func TestEscapeGitHub(t *testing.T) {
+	require.Equal(t, "100%25 done%0Anext: line", escapeGitHubData("100% done\nnext: line"))
+	require.Equal(t, "a%3A b%2C c%25%0D%0A", escapeGitHubProperty("a: b, c%\r\n"))
+}
@@ -0,0 +1,81 @@
+package formatter
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	jsonencoding "encoding/json"
+
+	"github.com/kubepug/kubepug/pkg/results"
+)
+
+type gitlab struct{}
+
+func newGitLabFormatter() Formatter {
+	return &gitlab{}
+}
+
+// gitlabIssue is an issue of a GitLab Code Quality report, as defined on
+// https://docs.gitlab.com/ee/ci/testing/code_quality.html#implement-a-custom-tool
+type gitlabIssue struct {
+	Type        string         `json:"type"`
+	CheckName   string         `json:"check_name"`
+	Description string         `json:"description"`
+	Categories  []string       `json:"categories"`
+	Severity    string         `json:"severity"`
+	Fingerprint string         `json:"fingerprint"`
+	Location    gitlabLocation `json:"location"`
+}
+
+type gitlabLocation struct {
+	Path  string      `json:"path"`
+	Lines gitlabLines `json:"lines"`
+}
+
+type gitlabLines struct {
+	Begin int `json:"begin"`
+}
+
+// Output writes a GitLab Code Quality report with an issue for each object using a deleted (critical)
+// or deprecated (minor) API. The report is about files, so objects not read from files, like the ones of Helm
+// releases, chart templates or clusters, are not reported
+func (f *gitlab) Output(data results.Result) ([]byte, error) {
+	issues := make([]gitlabIssue, 0)
+	add := func(apis []results.ResultItem, severity, status string) {
+		for i := range apis {
+			api := &apis[i]
+			for _, item := range api.Items {
+				// Code Quality issues must be on a line of a file
+				if !item.File || item.Line == 0 {
+					continue
+				}
+
+				issue := gitlabIssue{
+					Type:        "issue",
+					CheckName:   resultID(api),
+					Description: itemMessage(api, &item, status),
+					Categories:  []string{"Compatibility"},
+					Severity:    severity,
+					Location: gitlabLocation{
+						Path:  filePath(item.Location),
+						Lines: gitlabLines{Begin: item.Line},
+					},
+				}
+				issue.Fingerprint = gitlabFingerprint(&issue, &item)
+				issues = append(issues, issue)
+			}
+		}
+	}
+	add(data.DeletedAPIs, "critical", "deleted")
+	add(data.DeprecatedAPIs, "minor", "deprecated")
+
+	return jsonencoding.MarshalIndent(issues, "", "  ")
+}
+
+// gitlabFingerprint identifies an issue, so GitLab can compare the issues of a merge request
+// with the ones of the target branch. The document and line are not part of it, so moving an object,
+// or adding other objects before it, is not a new issue
+func gitlabFingerprint(issue *gitlabIssue, item *results.Item) string {
+	sum := sha256.Sum256([]byte(issue.CheckName + "\x00" + issue.Location.Path + "\x00" +
+		item.Namespace + "\x00" + item.ObjectName))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,84 @@
+package formatter
+
+import (
+	jsonencoding "encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	apis "github.com/kubepug/kubepug/pkg/apis/v1alpha1"
+	"github.com/kubepug/kubepug/pkg/results"
+)
+
+func TestGitLabOutput(t *testing.T) {
+	f := &gitlab{}
+
+	out, err := f.Output(results.Result{
+		DeprecatedAPIs: []results.ResultItem{
+			{
+				Group: "policy", Version: "v1beta1", Kind: "PodDisruptionBudget", K8sVersion: "1.21",
+				Items: []results.Item{
+					{Scope: "OBJECT", ObjectName: "web", Namespace: "apps", Location: "pdb.yaml", Line: 1, Column: 1, File: true},
+					{Scope: "OBJECT", ObjectName: "app", Namespace: "default", Location: "helm:default/app@3"},
+					{Scope: "OBJECT", ObjectName: "chart", Namespace: "default", Location: "templates/pdb.yaml"},
+				},
+			},
+		},
+		DeletedAPIs: []results.ResultItem{
+			{
+				Group: "extensions", Version: "v1beta1", Kind: "Ingress", K8sVersion: "1.22",
+				Replacement: &apis.GroupVersionKind{Group: "networking.k8s.io", Version: "v1", Kind: "Ingress"},
+				Items: []results.Item{
+					{Scope: "OBJECT", ObjectName: "web", Namespace: "apps", Location: "./manifests/ingress.yaml", Line: 12, Column: 1, File: true},
+					{Scope: "OBJECT", ObjectName: "api", Namespace: "apps"},
+					results.RequestedItem("ingresses", "apiserver:/metrics"),
+					results.CallerItem("alice", "kubectl/v1.15.0", 3),
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	var issues []gitlabIssue
+	require.NoError(t, jsonencoding.Unmarshal(out, &issues))
+	require.Len(t, issues, 2)
+
+	for i := range issues {
+		require.Len(t, issues[i].Fingerprint, 64)
+		issues[i].Fingerprint = ""
+	}
+	require.Equal(t, []gitlabIssue{
+		{
+			Type:        "issue",
+			CheckName:   "extensions/v1beta1/Ingress",
+			Description: "Ingress apps/web uses extensions/v1beta1 Ingress, which is deleted. It should be migrated to networking.k8s.io/v1/Ingress",
+			Categories:  []string{"Compatibility"},
+			Severity:    "critical",
+			Location:    gitlabLocation{Path: "manifests/ingress.yaml", Lines: gitlabLines{Begin: 12}},
+		},
+		{
+			Type:        "issue",
+			CheckName:   "policy/v1beta1/PodDisruptionBudget",
+			Description: "PodDisruptionBudget apps/web uses policy/v1beta1 PodDisruptionBudget, which is deprecated",
+			Categories:  []string{"Compatibility"},
+			Severity:    "minor",
+			Location:    gitlabLocation{Path: "pdb.yaml", Lines: gitlabLines{Begin: 1}},
+		},
+	}, issues)
+}
+
+func TestGitLabFingerprint(t *testing.T) {
+	issue := &gitlabIssue{CheckName: "batch/v1beta1/CronJob", Location: gitlabLocation{Path: "cronjob.yaml"}}
+	item := &results.Item{ObjectName: "cleanup", Namespace: "jobs", Document: 1, Line: 1}
+	moved := &results.Item{ObjectName: "cleanup", Namespace: "jobs", Document: 3, Line: 20}
+	other := &results.Item{ObjectName: "backup", Namespace: "jobs", Line: 1}
+
+	require.Equal(t, gitlabFingerprint(issue, item), gitlabFingerprint(issue, moved))
+	require.NotEqual(t, gitlabFingerprint(issue, item), gitlabFingerprint(issue, other))
+}
+
+func TestGitLabOutputEmpty(t *testing.T) {
+	out, err := (&gitlab{}).Output(results.Result{})
+	require.NoError(t, err)
+	require.Equal(t, "[]", string(out))
+}
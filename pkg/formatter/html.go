@@ -0,0 +1,101 @@
+package formatter
+
+import (
+	"bytes"
+	"html/template"
+
+	"github.com/kubepug/kubepug/pkg/results"
+)
+
+type html struct{}
+
+func newHTMLFormatter() Formatter {
+	return &html{}
+}
+
+// htmlTemplate renders the report as a single HTML file, with no external stylesheets or scripts
+var htmlTemplate = template.Must(template.New("html").Funcs(template.FuncMap{
+	"clusterStatus": clusterStatus,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Kubepug report</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em auto; max-width: 1100px; color: #24292f; padding: 0 1em; }
+table { border-collapse: collapse; margin: 1em 0; }
+th, td { border: 1px solid #d0d7de; padding: 6px 12px; text-align: left; }
+th { background: #f6f8fa; }
+.summary td { font-size: 1.5em; text-align: center; }
+.deleted { color: #cf222e; }
+.deprecated { color: #9a6700; }
+details { margin: 0.3em 0 0.3em 1em; }
+summary { cursor: pointer; }
+footer { margin-top: 2em; color: #57606a; font-size: 0.9em; }
+</style>
+</head>
+<body>
+<h1>Kubepug report</h1>
+<table class="summary">
+<tr><th>Deleted APIs</th><th>Deprecated APIs</th><th>Affected objects</th></tr>
+<tr><td class="deleted">{{ .DeletedAPIs }}</td><td class="deprecated">{{ .DeprecatedAPIs }}</td><td>{{ .Objects }}</td></tr>
+</table>
+{{- if not .Sections }}
+<p>No deprecated or deleted APIs found</p>
+{{- end }}
+{{- range .Sections }}
+<h2>{{ .Title }}</h2>
+<table>
+<tr><th>API</th><th>{{ .Column }}</th><th>Replacement</th><th>Objects</th></tr>
+{{- range .APIs }}
+<tr><td>{{ .Name }}</td><td>{{ .Version }}</td><td>{{ if .ReplacementLink }}<a href="{{ .ReplacementLink }}">{{ .Replacement }}</a>{{ else }}{{ .Replacement }}{{ end }}</td><td>{{ .Objects }}</td></tr>
+{{- end }}
+</table>
+{{- range .APIs }}
+<h3>{{ .Name }}</h3>
+{{- if .Description }}
+<p>{{ .Description }}</p>
+{{- end }}
+{{- range .Groups }}
+<details>
+<summary>{{ .Name }} ({{ len .Items }})</summary>
+<ul>
+{{- range .Items }}
+<li>{{ . }}</li>
+{{- end }}
+</ul>
+</details>
+{{- end }}
+{{- end }}
+{{- end }}
+{{- if .StoredVersions }}
+<h2>CRDs storing versions to be migrated</h2>
+<table>
+<tr><th>CRD</th><th>Stored version</th><th>Reason</th><th>Storage version</th><th>Cluster</th></tr>
+{{- range .StoredVersions }}
+<tr><td>{{ .CRD }}</td><td>{{ .Group }}/{{ .Version }}</td><td>{{ .Reason }}</td><td>{{ .StorageVersion }}</td><td>{{ .Cluster }}</td></tr>
+{{- end }}
+</table>
+{{- end }}
+{{- if .Clusters }}
+<h2>Clusters</h2>
+<table>
+<tr><th>Cluster</th><th>Deprecated</th><th>Deleted</th><th>Stored versions</th><th>Status</th></tr>
+{{- range .Clusters }}
+<tr><td>{{ .Name }}</td><td>{{ .DeprecatedAPIs }}</td><td>{{ .DeletedAPIs }}</td><td>{{ .StoredVersions }}</td><td>{{ clusterStatus . }}</td></tr>
+{{- end }}
+</table>
+{{- end }}
+<footer>` + footer + `</footer>
+</body>
+</html>
+`))
+
+// Output renders the results as a self-contained HTML report, with the same content of the markdown format
+func (f *html) Output(data results.Result) ([]byte, error) {
+	var out bytes.Buffer
+	if err := htmlTemplate.Execute(&out, newReport(&data)); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
@@ -0,0 +1,36 @@
+package formatter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubepug/kubepug/pkg/results"
+)
+
+func TestHTMLOutput(t *testing.T) {
+	out, err := (&html{}).Output(junitResult)
+	require.NoError(t, err)
+	require.Contains(t, string(out), `<tr><td class="deleted">1</td><td class="deprecated">1</td><td>3</td></tr>`)
+	require.Contains(t, string(out), `<tr><td>extensions/v1beta1 Ingress</td><td>1.22</td><td><a href="https://kubernetes.io/docs/reference/generated/kubernetes-api/v1.22/#ingress-v1-networking-k8s-io">networking.k8s.io/v1/Ingress</a></td><td>2</td></tr>`)
+	require.Contains(t, string(out), "<summary>Namespace jobs (1)</summary>\n<ul>\n<li>cleanup (location: manifests/cronjob.yaml:3:1)</li>\n</ul>\n</details>")
+	// The report should not depend on external files
+	require.NotContains(t, string(out), "<link")
+	require.NotContains(t, string(out), "<script")
+}
+
+func TestHTMLOutputEscaping(t *testing.T) {
+	out, err := (&html{}).Output(results.Result{
+		DeletedAPIs: []results.ResultItem{
+			{
+				Group: "example.com", Version: "v1", Kind: "Widget", Description: "<script>alert(1)</script>",
+				Items: []results.Item{{Scope: "OBJECT", ObjectName: "a&b", Namespace: "apps"}},
+			},
+		},
+		Clusters: []results.ClusterSummary{{Name: "staging", Error: "connection refused"}},
+	})
+	require.NoError(t, err)
+	require.Contains(t, string(out), "<p>&lt;script&gt;alert(1)&lt;/script&gt;</p>")
+	require.Contains(t, string(out), "<li>a&amp;b</li>")
+	require.Contains(t, string(out), "<td>FAILED: connection refused</td>")
+}
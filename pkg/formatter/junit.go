@@ -0,0 +1,123 @@
+package formatter
+
+import (
+	"encoding/xml"
+
+	"github.com/kubepug/kubepug/pkg/results"
+)
+
+type junit struct {
+	skipDeprecated bool
+}
+
+func newJUnitFormatter(skipDeprecated bool) Formatter {
+	return &junit{
+		skipDeprecated: skipDeprecated,
+	}
+}
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Skipped  int             `xml:"skipped,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	File      string        `xml:"file,attr,omitempty"`
+	Line      int           `xml:"line,attr,omitempty"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Skipped   *junitSkipped `xml:"skipped,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+type junitSkipped struct {
+	Message string `xml:"message,attr"`
+}
+
+// Output converts the results into a JUnit XML report. Each deprecated or deleted Group/Version/Kind
+// (and field) is a test suite, and each object using it is a failed, or skipped, test case. When
+// nothing is found, a kubepug test suite with a single passed test case is reported
+func (f *junit) Output(data results.Result) ([]byte, error) {
+	report := junitTestSuites{
+		Name:   "kubepug",
+		Suites: make([]junitTestSuite, 0),
+	}
+
+	add := func(apis []results.ResultItem, status string, skip bool) {
+		for i := range apis {
+			api := &apis[i]
+			suite := junitTestSuite{Name: resultID(api)}
+			for _, item := range api.Items {
+				testCase := junitTestCase{
+					Name:      itemName(&item),
+					Classname: suite.Name,
+					File:      item.Location,
+					Line:      item.Line,
+				}
+				if skip {
+					testCase.Skipped = &junitSkipped{Message: details(api, status)}
+					suite.Skipped++
+				} else {
+					testCase.Failure = &junitFailure{Message: summary(api, status), Type: status, Text: details(api, status)}
+					suite.Failures++
+				}
+				suite.Cases = append(suite.Cases, testCase)
+			}
+			suite.Tests = len(suite.Cases)
+
+			report.Tests += suite.Tests
+			report.Failures += suite.Failures
+			report.Skipped += suite.Skipped
+			report.Suites = append(report.Suites, suite)
+		}
+	}
+	add(data.DeletedAPIs, "deleted", false)
+	add(data.DeprecatedAPIs, "deprecated", f.skipDeprecated)
+
+	// CI systems like Jenkins fail on reports without any test, so a passed test is reported
+	// when nothing was found
+	if report.Tests == 0 {
+		report.Tests = 1
+		report.Suites = append(report.Suites, junitTestSuite{
+			Name:  "kubepug",
+			Tests: 1,
+			Cases: []junitTestCase{{Name: "no deprecated or deleted APIs found", Classname: "kubepug"}},
+		})
+	}
+
+	out, err := xml.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), append(out, '\n')...), nil
+}
+
+// itemName identifies an item, like the namespace and name of an object or the user of a caller
+func itemName(item *results.Item) string {
+	switch {
+	case item.Scope == "CALLER":
+		return "user " + item.User
+	case item.Namespace != "":
+		return item.Namespace + "/" + item.ObjectName
+	default:
+		return item.ObjectName
+	}
+}
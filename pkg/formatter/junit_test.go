@@ -0,0 +1,73 @@
+package formatter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	apis "github.com/kubepug/kubepug/pkg/apis/v1alpha1"
+	"github.com/kubepug/kubepug/pkg/results"
+)
+
+var junitResult = results.Result{
+	DeprecatedAPIs: []results.ResultItem{
+		{
+			Group: "batch", Version: "v1beta1", Kind: "CronJob", K8sVersion: "1.21",
+			Replacement: &apis.GroupVersionKind{Group: "batch", Version: "v1", Kind: "CronJob"},
+			Items:       []results.Item{{Scope: "OBJECT", ObjectName: "cleanup", Namespace: "jobs", Location: "manifests/cronjob.yaml", Line: 3, Column: 1}},
+		},
+	},
+	DeletedAPIs: []results.ResultItem{
+		{
+			Group: "extensions", Version: "v1beta1", Kind: "Ingress", K8sVersion: "1.22",
+			Description: "Ingress is a collection of rules.",
+			Replacement: &apis.GroupVersionKind{Group: "networking.k8s.io", Version: "v1", Kind: "Ingress"},
+			Items: []results.Item{
+				{Scope: "OBJECT", ObjectName: "web", Namespace: "apps"},
+				results.CallerItem("alice", "kubectl/v1.15.0", 3),
+			},
+		},
+	},
+}
+
+func TestJUnitOutput(t *testing.T) {
+	out, err := (&junit{}).Output(junitResult)
+	require.NoError(t, err)
+	require.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="kubepug" tests="3" failures="3" skipped="0">
+  <testsuite name="extensions/v1beta1/Ingress" tests="2" failures="2" skipped="0">
+    <testcase name="apps/web" classname="extensions/v1beta1/Ingress">
+      <failure message="extensions/v1beta1 Ingress is deleted since Kubernetes 1.22" type="deleted">extensions/v1beta1 Ingress is deleted since Kubernetes 1.22&#xA;Ingress is a collection of rules.&#xA;Replacement: networking.k8s.io/v1/Ingress</failure>
+    </testcase>
+    <testcase name="user alice" classname="extensions/v1beta1/Ingress">
+      <failure message="extensions/v1beta1 Ingress is deleted since Kubernetes 1.22" type="deleted">extensions/v1beta1 Ingress is deleted since Kubernetes 1.22&#xA;Ingress is a collection of rules.&#xA;Replacement: networking.k8s.io/v1/Ingress</failure>
+    </testcase>
+  </testsuite>
+  <testsuite name="batch/v1beta1/CronJob" tests="1" failures="1" skipped="0">
+    <testcase name="jobs/cleanup" classname="batch/v1beta1/CronJob" file="manifests/cronjob.yaml" line="3">
+      <failure message="batch/v1beta1 CronJob is deprecated since Kubernetes 1.21" type="deprecated">batch/v1beta1 CronJob is deprecated since Kubernetes 1.21&#xA;Replacement: batch/v1/CronJob</failure>
+    </testcase>
+  </testsuite>
+</testsuites>
+`, string(out))
+}
+
+func TestJUnitOutputSkipDeprecated(t *testing.T) {
+	out, err := newJUnitFormatter(true).Output(junitResult)
+	require.NoError(t, err)
+	require.Contains(t, string(out), `<testsuites name="kubepug" tests="3" failures="2" skipped="1">`)
+	require.Contains(t, string(out), `<testsuite name="batch/v1beta1/CronJob" tests="1" failures="0" skipped="1">`)
+	require.Contains(t, string(out), `<skipped message="batch/v1beta1 CronJob is deprecated since Kubernetes 1.21&#xA;Replacement: batch/v1/CronJob"></skipped>`)
+}
+
+func TestJUnitOutputNothingFound(t *testing.T) {
+	out, err := (&junit{}).Output(results.Result{})
+	require.NoError(t, err)
+	require.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="kubepug" tests="1" failures="0" skipped="0">
+  <testsuite name="kubepug" tests="1" failures="0" skipped="0">
+    <testcase name="no deprecated or deleted APIs found" classname="kubepug"></testcase>
+  </testsuite>
+</testsuites>
+`, string(out))
+}
@@ -0,0 +1,100 @@
+package formatter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/kubepug/kubepug/pkg/results"
+)
+
+type markdown struct{}
+
+func newMarkdownFormatter() Formatter {
+	return &markdown{}
+}
+
+// Output renders the results as a Markdown report, with the summary counts, a table for the deleted and
+// deprecated APIs and the objects using each API grouped by namespace on collapsible lists
+func (f *markdown) Output(data results.Result) ([]byte, error) {
+	r := newReport(&data)
+
+	s := sliceBuilder{}
+	s.add("# Kubepug report\n\n")
+	s.addMarkdownTable([]string{"Deleted APIs", "Deprecated APIs", "Affected objects"},
+		[][]string{{strconv.Itoa(r.DeletedAPIs), strconv.Itoa(r.DeprecatedAPIs), strconv.Itoa(r.Objects)}})
+
+	if len(r.Sections) == 0 {
+		s.add("No deprecated or deleted APIs found\n\n")
+	}
+
+	for _, section := range r.Sections {
+		s.add("## ", section.Title, "\n\n")
+
+		rows := make([][]string, 0, len(section.APIs))
+		for _, api := range section.APIs {
+			replacement := api.Replacement
+			if api.ReplacementLink != "" {
+				replacement = fmt.Sprintf("[%s](%s)", api.Replacement, api.ReplacementLink)
+			}
+			rows = append(rows, []string{api.Name, api.Version, replacement, strconv.Itoa(api.Objects)})
+		}
+		s.addMarkdownTable([]string{"API", section.Column, "Replacement", "Objects"}, rows)
+
+		for _, api := range section.APIs {
+			s.add("### ", api.Name, "\n\n")
+			if api.Description != "" {
+				s.add(api.Description, "\n\n")
+			}
+			for _, group := range api.Groups {
+				s.add("<details>\n<summary>", group.Name, " (", strconv.Itoa(len(group.Items)), ")</summary>\n\n")
+				for _, item := range group.Items {
+					s.add("- ", item, "\n")
+				}
+				s.add("\n</details>\n\n")
+			}
+		}
+	}
+
+	if len(r.StoredVersions) > 0 {
+		s.add("## CRDs storing versions to be migrated\n\n")
+		rows := make([][]string, 0, len(r.StoredVersions))
+		for _, stored := range r.StoredVersions {
+			rows = append(rows, []string{stored.CRD, stored.Group + "/" + stored.Version, stored.Reason, stored.StorageVersion, stored.Cluster})
+		}
+		s.addMarkdownTable([]string{"CRD", "Stored version", "Reason", "Storage version", "Cluster"}, rows)
+	}
+
+	if len(r.Clusters) > 0 {
+		s.add("## Clusters\n\n")
+		rows := make([][]string, 0, len(r.Clusters))
+		for _, cluster := range r.Clusters {
+			rows = append(rows, []string{cluster.Name, strconv.Itoa(cluster.DeprecatedAPIs), strconv.Itoa(cluster.DeletedAPIs), strconv.Itoa(cluster.StoredVersions), clusterStatus(cluster)})
+		}
+		s.addMarkdownTable([]string{"Cluster", "Deprecated", "Deleted", "Stored versions", "Status"}, rows)
+	}
+
+	s.add(footer, "\n")
+	return []byte(s.String()), nil
+}
+
+// addMarkdownTable adds a Markdown table, escaping the pipes of the cells
+func (b *sliceBuilder) addMarkdownTable(header []string, rows [][]string) {
+	b.add("| ", strings.Join(header, " | "), " |\n|", strings.Repeat(" --- |", len(header)), "\n")
+	for _, row := range rows {
+		cells := make([]string, 0, len(row))
+		for _, cell := range row {
+			cells = append(cells, strings.ReplaceAll(cell, "|", "\\|"))
+		}
+		b.add("| ", strings.Join(cells, " | "), " |\n")
+	}
+	b.add("\n")
+}
+
+// clusterStatus tells if a cluster was scanned or why it failed
+func clusterStatus(cluster results.ClusterSummary) string {
+	if cluster.Error != "" {
+		return "FAILED: " + cluster.Error
+	}
+	return "OK"
+}
@@ -0,0 +1,91 @@
+package formatter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubepug/kubepug/pkg/results"
+)
+
+const expectedMarkdown = `# Kubepug report
+
+| Deleted APIs | Deprecated APIs | Affected objects |
+| --- | --- | --- |
+| 1 | 1 | 3 |
+
+## Deleted APIs
+
+| API | Deleted at | Replacement | Objects |
+| --- | --- | --- | --- |
+| extensions/v1beta1 Ingress | 1.22 | [networking.k8s.io/v1/Ingress](https://kubernetes.io/docs/reference/generated/kubernetes-api/v1.22/#ingress-v1-networking-k8s-io) | 2 |
+
+### extensions/v1beta1 Ingress
+
+Ingress is a collection of rules.
+
+<details>
+<summary>Callers (1)</summary>
+
+- alice (user-agent: kubectl/v1.15.0, calls: 3)
+
+</details>
+
+<details>
+<summary>Namespace apps (1)</summary>
+
+- web
+
+</details>
+
+## Deprecated APIs
+
+| API | Deprecated at | Replacement | Objects |
+| --- | --- | --- | --- |
+| batch/v1beta1 CronJob | 1.21 | [batch/v1/CronJob](https://kubernetes.io/docs/reference/generated/kubernetes-api/v1.21/#cronjob-v1-batch) | 1 |
+
+### batch/v1beta1 CronJob
+
+<details>
+<summary>Namespace jobs (1)</summary>
+
+- cleanup (location: manifests/cronjob.yaml:3:1)
+
+</details>
+
+` + footer + "\n"
+
+func TestMarkdownOutput(t *testing.T) {
+	out, err := (&markdown{}).Output(junitResult)
+	require.NoError(t, err)
+	require.Equal(t, expectedMarkdown, string(out))
+}
+
+func TestMarkdownOutputDetails(t *testing.T) {
+	out, err := (&markdown{}).Output(results.Result{
+		DeprecatedAPIs: []results.ResultItem{
+			{
+				Group: "", Version: "v1", Kind: "Pod", Field: "spec.serviceAccount", K8sVersion: "1.1",
+				Items: []results.Item{{Scope: "GLOBAL", ObjectName: "node-a", Rule: "a|b", Cluster: "prod"}},
+			},
+		},
+		StoredVersions: []results.StoredVersionItem{
+			{CRD: "widgets.example.com", Group: "example.com", Version: "v1beta1", StorageVersion: "v1", Reason: "deprecated"},
+		},
+		Clusters: []results.ClusterSummary{
+			{Name: "prod", DeprecatedAPIs: 1},
+			{Name: "staging", Error: "connection refused"},
+		},
+	})
+	require.NoError(t, err)
+	require.Contains(t, string(out), "| field spec.serviceAccount of v1 Pod | 1.1 |  | 1 |\n")
+	require.Contains(t, string(out), "<summary>Cluster scoped (1)</summary>\n\n- node-a (rule: a|b, cluster: prod)\n")
+	require.Contains(t, string(out), "| widgets.example.com | example.com/v1beta1 | deprecated | v1 |  |\n")
+	require.Contains(t, string(out), "| staging | 0 | 0 | 0 | FAILED: connection refused |\n")
+}
+
+func TestMarkdownOutputEmpty(t *testing.T) {
+	out, err := (&markdown{}).Output(results.Result{})
+	require.NoError(t, err)
+	require.Contains(t, string(out), "| 0 | 0 | 0 |\n\nNo deprecated or deleted APIs found\n")
+}
@@ -0,0 +1,106 @@
+package formatter
+
+import (
+	jsonencoding "encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+	yamlencoder "gopkg.in/yaml.v3"
+
+	"github.com/kubepug/kubepug/pkg/planner"
+)
+
+// PlanFormatter defines the behavior for a Formatter of upgrade plans
+type PlanFormatter interface {
+	OutputPlan(plan planner.Plan) ([]byte, error)
+}
+
+// NewPlanFormatterWithError returns a formatter of upgrade plans or an error in case the formatter is invalid
+func NewPlanFormatterWithError(t string) (PlanFormatter, error) {
+	switch t {
+	case "stdout":
+		return &stdout{plain: false}, nil
+	case "plain":
+		return &stdout{plain: true}, nil
+	case "json":
+		return &json{}, nil
+	case "yaml":
+		return &yaml{}, nil
+	default:
+		return nil, fmt.Errorf("invalid formatter selected: %s", t)
+	}
+}
+
+func (f *json) OutputPlan(plan planner.Plan) ([]byte, error) {
+	return jsonencoding.Marshal(plan)
+}
+
+func (f *yaml) OutputPlan(plan planner.Plan) ([]byte, error) {
+	return yamlencoder.Marshal(plan)
+}
+
+func (f *stdout) OutputPlan(plan planner.Plan) ([]byte, error) {
+	color.NoColor = f.plain
+
+	s := sliceBuilder{}
+	s.add(resourceColor("UPGRADE PLAN"), ": ", plan.Current, " -> ", plan.Target, "\n\n")
+
+	for i, hop := range plan.Hops {
+		title := hop.Version
+		if i == 0 {
+			title += " (current)"
+		}
+		s.add(resourceColor(title), ":\n")
+		if len(hop.Deprecated) == 0 && len(hop.Removed) == 0 {
+			s.add("\t ├─ No APIs in use are deprecated or removed\n")
+		}
+		for _, api := range hop.Removed {
+			s.add("\t ├─ ", errorColor("REMOVED"), " ", gvkString(api.Group, api.Version, api.Kind), "\n")
+		}
+		for _, api := range hop.Deprecated {
+			s.add("\t ├─ ", namespaceColor("Deprecated"), " ", gvkString(api.Group, api.Version, api.Kind), "\n")
+		}
+		s.add("\n")
+	}
+
+	s.add(resourceColor("Migration checklist"), ":\n")
+	if len(plan.Checklist) == 0 {
+		s.add("\nNo migrations needed")
+	}
+	for i, migration := range plan.Checklist {
+		s.add("[ ] ", strconv.Itoa(i+1), ". ", gvColor(gvkString(migration.Group, migration.Version, migration.Kind)))
+		if migration.Replacement != nil {
+			s.add(" -> ", gvkString(migration.Replacement.Group, migration.Replacement.Version, migration.Replacement.Kind), replacementAvailability(migration.ReplacementAvailable))
+		}
+		s.add("\n")
+		switch {
+		case migration.MigrateBy == "":
+			s.add("\t ├─ ", namespaceColor("Deprecated at:"), " ", migration.DeprecatedIn, ", not removed up to ", plan.Target, "\n")
+		case migration.MigrateBy == plan.Current:
+			s.add("\t ├─ ", namespaceColor("Removed at:"), " ", migration.RemovedIn, ", ", errorColor("MIGRATE BEFORE THE FIRST UPGRADE"), "\n")
+		default:
+			s.add("\t ├─ ", namespaceColor("Removed at:"), " ", migration.RemovedIn, ", can be deferred until running ", migration.MigrateBy, "\n")
+		}
+		s.addItems(migration.Items)
+	}
+
+	s.add("\n")
+	out := s.String()
+	if f.plain {
+		out = strings.ReplaceAll(out, "\t", "")
+	}
+	return []byte(out), nil
+}
+
+func gvkString(group, version, kind string) string {
+	gvk := version
+	if group != "" {
+		gvk = group + "/" + version
+	}
+	if kind != "" {
+		gvk += "/" + kind
+	}
+	return gvk
+}
@@ -0,0 +1,63 @@
+package formatter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	apis "github.com/kubepug/kubepug/pkg/apis/v1alpha1"
+	"github.com/kubepug/kubepug/pkg/planner"
+	"github.com/kubepug/kubepug/pkg/results"
+)
+
+var mockPlan = planner.Plan{
+	Current: "1.24",
+	Target:  "1.26",
+	Hops: []planner.Hop{
+		{Version: "1.24", Deprecated: []results.ResultItem{{Group: "batch", Version: "v1beta1", Kind: "CronJob", K8sVersion: "1.21"}}},
+		{Version: "1.25", Removed: []results.ResultItem{{Group: "batch", Version: "v1beta1", Kind: "CronJob", K8sVersion: "1.25"}}},
+		{Version: "1.26"},
+	},
+	Checklist: []planner.Migration{
+		{
+			Group: "batch", Version: "v1beta1", Kind: "CronJob",
+			Replacement:  &apis.GroupVersionKind{Group: "batch", Version: "v1", Kind: "CronJob"},
+			DeprecatedIn: "1.21", RemovedIn: "1.25", MigrateBy: "1.24",
+			Items: []results.Item{{Scope: "OBJECT", ObjectName: "backup", Namespace: "apps"}},
+		},
+		{Version: "v1", Kind: "ComponentStatus", DeprecatedIn: "1.19"},
+	},
+}
+
+func TestNewPlanFormatterWithError(t *testing.T) {
+	for _, format := range []string{"stdout", "plain", "json", "yaml"} {
+		f, err := NewPlanFormatterWithError(format)
+		require.NoError(t, err)
+		require.NotNil(t, f)
+	}
+
+	_, err := NewPlanFormatterWithError("xpto")
+	require.ErrorContains(t, err, "invalid formatter selected: xpto")
+}
+
+func TestStdoutOutputPlan(t *testing.T) {
+	f := &stdout{plain: true}
+
+	out, err := f.OutputPlan(mockPlan)
+	require.NoError(t, err)
+	require.Contains(t, string(out), "UPGRADE PLAN: 1.24 -> 1.26\n\n"+
+		"1.24 (current):\n ├─ Deprecated batch/v1beta1/CronJob\n\n"+
+		"1.25:\n ├─ REMOVED batch/v1beta1/CronJob\n\n"+
+		"1.26:\n ├─ No APIs in use are deprecated or removed\n\n")
+	require.Contains(t, string(out), "[ ] 1. batch/v1beta1/CronJob -> batch/v1/CronJob\n"+
+		" ├─ Removed at: 1.25, MIGRATE BEFORE THE FIRST UPGRADE\n"+
+		"-> OBJECT: backup namespace: apps \n")
+	require.Contains(t, string(out), "[ ] 2. v1/ComponentStatus\n ├─ Deprecated at: 1.19, not removed up to 1.26\n")
+}
+
+func TestJSONOutputPlan(t *testing.T) {
+	out, err := (&json{}).OutputPlan(mockPlan)
+	require.NoError(t, err)
+	require.Contains(t, string(out), `"migrate_by":"1.24"`)
+	require.Contains(t, string(out), `"removed":[{"group":"batch","kind":"CronJob","version":"v1beta1","k8sversion":"1.25"}]`)
+}
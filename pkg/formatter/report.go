@@ -0,0 +1,168 @@
+package formatter
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/kubepug/kubepug/pkg/results"
+)
+
+const (
+	apiReferenceURL = "https://kubernetes.io/docs/reference/generated/kubernetes-api"
+
+	clusterScopedGroup = "Cluster scoped"
+	callersGroup       = "Callers"
+	requestsGroup      = "Requests"
+)
+
+var minorVersion = regexp.MustCompile(`^\d+\.\d+$`)
+
+// report is the model shared by the markdown and html formatters
+type report struct {
+	DeletedAPIs    int
+	DeprecatedAPIs int
+	Objects        int
+	StoredVersions []results.StoredVersionItem
+	Clusters       []results.ClusterSummary
+	Sections       []reportSection
+}
+
+// reportSection contains the deleted or the deprecated APIs
+type reportSection struct {
+	Title string
+	// Column is the title of the column containing the version the APIs were deleted or deprecated at
+	Column string
+	APIs   []reportAPI
+}
+
+type reportAPI struct {
+	Name            string
+	Version         string
+	Description     string
+	Replacement     string
+	ReplacementLink string
+	Objects         int
+	Groups          []reportGroup
+}
+
+// reportGroup contains the items of a namespace, or the items that are not namespaced, like callers
+type reportGroup struct {
+	Name  string
+	Items []string
+}
+
+func newReport(data *results.Result) *report {
+	r := &report{
+		DeletedAPIs:    len(data.DeletedAPIs),
+		DeprecatedAPIs: len(data.DeprecatedAPIs),
+		StoredVersions: data.StoredVersions,
+		Clusters:       data.Clusters,
+	}
+
+	add := func(title, column string, apis []results.ResultItem) {
+		if len(apis) == 0 {
+			return
+		}
+		section := reportSection{Title: title, Column: column}
+		for i := range apis {
+			api := newReportAPI(&apis[i])
+			r.Objects += api.Objects
+			section.APIs = append(section.APIs, api)
+		}
+		r.Sections = append(r.Sections, section)
+	}
+	add("Deleted APIs", "Deleted at", data.DeletedAPIs)
+	add("Deprecated APIs", "Deprecated at", data.DeprecatedAPIs)
+
+	return r
+}
+
+func newReportAPI(api *results.ResultItem) reportAPI {
+	r := reportAPI{
+		Name:        subject(api),
+		Description: strings.ReplaceAll(api.Description, "\n", " "),
+		Objects:     len(api.Items),
+	}
+	if api.K8sVersion != "unknown" {
+		r.Version = api.K8sVersion
+	}
+	if api.Replacement != nil {
+		r.Replacement = api.Replacement.Group + "/" + api.Replacement.Version + "/" + api.Replacement.Kind
+		r.ReplacementLink = replacementLink(api)
+	}
+
+	groups := make(map[string][]string)
+	for i := range api.Items {
+		name := itemGroup(&api.Items[i])
+		groups[name] = append(groups[name], itemDetails(&api.Items[i]))
+	}
+	for name, items := range groups {
+		r.Groups = append(r.Groups, reportGroup{Name: name, Items: items})
+	}
+	sort.Slice(r.Groups, func(i, j int) bool { return r.Groups[i].Name < r.Groups[j].Name })
+	return r
+}
+
+// replacementLink returns the link to the Kubernetes API reference of the replacement, on the version the
+// API was deprecated or deleted at. It is empty for replacements that are not Kubernetes APIs
+func replacementLink(api *results.ResultItem) string {
+	group := api.Replacement.Group
+	if strings.Contains(group, ".") && !strings.HasSuffix(group, ".k8s.io") {
+		return ""
+	}
+	if !minorVersion.MatchString(api.K8sVersion) {
+		return ""
+	}
+	if group == "" {
+		group = "core"
+	}
+	return fmt.Sprintf("%s/v%s/#%s-%s-%s", apiReferenceURL, api.K8sVersion,
+		strings.ToLower(api.Replacement.Kind), api.Replacement.Version, strings.ReplaceAll(group, ".", "-"))
+}
+
+// itemGroup returns the group an item is listed in, being its namespace for namespaced objects
+func itemGroup(item *results.Item) string {
+	switch {
+	case item.Scope == "CALLER":
+		return callersGroup
+	case item.Scope == "REQUESTED":
+		return requestsGroup
+	case item.Namespace != "":
+		return "Namespace " + item.Namespace
+	default:
+		return clusterScopedGroup
+	}
+}
+
+// itemDetails describes an item, with where it was found and the details of its scope
+func itemDetails(item *results.Item) string {
+	name := item.ObjectName
+	var details []string
+	if item.Scope == "CALLER" {
+		name = item.User
+		userAgent := item.UserAgent
+		if userAgent == "" {
+			userAgent = "unknown"
+		}
+		details = append(details, "user-agent: "+userAgent, fmt.Sprintf("calls: %d", item.Calls))
+	}
+	if item.Location != "" {
+		details = append(details, "location: "+item.FileLocation())
+	}
+	if item.FieldManager != "" {
+		details = append(details, "manager: "+item.FieldManager)
+	}
+	if item.Rule != "" {
+		details = append(details, "rule: "+item.Rule)
+	}
+	if item.Cluster != "" {
+		details = append(details, "cluster: "+item.Cluster)
+	}
+
+	if len(details) == 0 {
+		return name
+	}
+	return fmt.Sprintf("%s (%s)", name, strings.Join(details, ", "))
+}
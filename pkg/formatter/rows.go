@@ -0,0 +1,165 @@
+package formatter
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kubepug/kubepug/pkg/results"
+)
+
+// columns available to the csv and table formats
+const (
+	columnStatus      = "STATUS"
+	columnGroup       = "GROUP"
+	columnVersion     = "VERSION"
+	columnKind        = "KIND"
+	columnField       = "FIELD"
+	columnNamespace   = "NAMESPACE"
+	columnName        = "NAME"
+	columnLocation    = "LOCATION"
+	columnReplacement = "REPLACEMENT"
+	columnK8sVersion  = "K8S-VERSION"
+	columnScope       = "SCOPE"
+	columnManager     = "MANAGER"
+	columnCluster     = "CLUSTER"
+)
+
+// defaultColumns are the columns of the csv and table formats when no column is selected
+var defaultColumns = []string{
+	columnStatus, columnGroup, columnVersion, columnKind, columnNamespace,
+	columnName, columnLocation, columnReplacement, columnK8sVersion,
+}
+
+// rowValues return the value of each column for an affected object
+var rowValues = map[string]func(api *results.ResultItem, item *results.Item, status string) string{
+	columnStatus:  func(_ *results.ResultItem, _ *results.Item, status string) string { return status },
+	columnGroup:   func(api *results.ResultItem, _ *results.Item, _ string) string { return api.Group },
+	columnVersion: func(api *results.ResultItem, _ *results.Item, _ string) string { return api.Version },
+	columnKind:    func(api *results.ResultItem, _ *results.Item, _ string) string { return api.Kind },
+	columnField:   func(api *results.ResultItem, _ *results.Item, _ string) string { return api.Field },
+	columnNamespace: func(_ *results.ResultItem, item *results.Item, _ string) string {
+		return item.Namespace
+	},
+	columnName: func(_ *results.ResultItem, item *results.Item, _ string) string {
+		if item.Scope == "CALLER" {
+			return item.User
+		}
+		return item.ObjectName
+	},
+	columnLocation: func(_ *results.ResultItem, item *results.Item, _ string) string {
+		return item.FileLocation()
+	},
+	columnReplacement: func(api *results.ResultItem, _ *results.Item, _ string) string {
+		if api.Replacement == nil {
+			return ""
+		}
+		return api.Replacement.Group + "/" + api.Replacement.Version + "/" + api.Replacement.Kind
+	},
+	columnK8sVersion: func(api *results.ResultItem, _ *results.Item, _ string) string { return api.K8sVersion },
+	columnScope:      func(_ *results.ResultItem, item *results.Item, _ string) string { return item.Scope },
+	columnManager:    func(_ *results.ResultItem, item *results.Item, _ string) string { return item.FieldManager },
+	columnCluster:    func(_ *results.ResultItem, item *results.Item, _ string) string { return item.Cluster },
+}
+
+// rowsConfig are the columns, and the column the rows are sorted by, of the csv and table formats
+type rowsConfig struct {
+	columns []string
+	sortBy  string
+}
+
+// newRowsConfig validates the columns and the sort column, which are case insensitive
+func newRowsConfig(columns []string, sortBy string) (rowsConfig, error) {
+	if len(columns) == 0 {
+		columns = defaultColumns
+	}
+
+	config := rowsConfig{columns: make([]string, 0, len(columns))}
+	for _, column := range columns {
+		column = strings.ToUpper(strings.TrimSpace(column))
+		if _, ok := rowValues[column]; !ok {
+			return config, fmt.Errorf("invalid column %s, should be one of %s", column, strings.Join(availableColumns(), ", "))
+		}
+		config.columns = append(config.columns, column)
+	}
+
+	if sortBy != "" {
+		config.sortBy = strings.ToUpper(strings.TrimSpace(sortBy))
+		if _, ok := rowValues[config.sortBy]; !ok {
+			return config, fmt.Errorf("invalid sort column %s, should be one of %s", sortBy, strings.Join(availableColumns(), ", "))
+		}
+	}
+	return config, nil
+}
+
+func availableColumns() []string {
+	columns := make([]string, 0, len(rowValues))
+	for column := range rowValues {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+	return columns
+}
+
+// rows returns a row for each object affected by a deleted or deprecated API, with the selected columns
+func (c *rowsConfig) rows(data *results.Result) [][]string {
+	type row struct {
+		cells []string
+		key   string
+	}
+	var all []row
+
+	add := func(apis []results.ResultItem, status string) {
+		for i := range apis {
+			items := apis[i].Items
+			if len(items) == 0 {
+				// APIs with no items are still reported, with no object
+				items = []results.Item{{}}
+			}
+			for j := range items {
+				r := row{cells: make([]string, 0, len(c.columns))}
+				for _, column := range c.columns {
+					r.cells = append(r.cells, rowValues[column](&apis[i], &items[j], status))
+				}
+				if c.sortBy != "" {
+					r.key = rowValues[c.sortBy](&apis[i], &items[j], status)
+				}
+				all = append(all, r)
+			}
+		}
+	}
+	add(data.DeletedAPIs, "deleted")
+	add(data.DeprecatedAPIs, "deprecated")
+
+	if c.sortBy != "" {
+		sort.SliceStable(all, func(i, j int) bool {
+			if c.sortBy == columnK8sVersion {
+				return lessVersion(all[i].key, all[j].key)
+			}
+			return all[i].key < all[j].key
+		})
+	}
+
+	rows := make([][]string, 0, len(all))
+	for i := range all {
+		rows = append(rows, all[i].cells)
+	}
+	return rows
+}
+
+// lessVersion compares Kubernetes versions like 1.9 and 1.22 by their numbers, instead of as text
+func lessVersion(a, b string) bool {
+	partsA, partsB := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(partsA) && i < len(partsB); i++ {
+		numberA, errA := strconv.Atoi(partsA[i])
+		numberB, errB := strconv.Atoi(partsB[i])
+		if errA != nil || errB != nil {
+			return a < b
+		}
+		if numberA != numberB {
+			return numberA < numberB
+		}
+	}
+	return len(partsA) < len(partsB)
+}
@@ -0,0 +1,198 @@
+package formatter
+
+import (
+	jsonencoding "encoding/json"
+	"net/url"
+	"path/filepath"
+
+	"sigs.k8s.io/release-utils/version"
+
+	"github.com/kubepug/kubepug/pkg/results"
+)
+
+const (
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion = "2.1.0"
+	kubepugURI   = "https://github.com/kubepug/kubepug"
+)
+
+type sarif struct{}
+
+func newSARIFFormatter() Formatter {
+	return &sarif{}
+}
+
+// The types below are the subset of SARIF 2.1.0 used by Kubepug, as defined on
+// https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	FullDescription      *sarifMessage      `json:"fullDescription,omitempty"`
+	Help                 sarifMessage       `json:"help"`
+	HelpURI              string             `json:"helpUri,omitempty"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	RuleIndex int             `json:"ruleIndex"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation *sarifPhysicalLocation `json:"physicalLocation,omitempty"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+type sarifLogicalLocation struct {
+	Name               string `json:"name,omitempty"`
+	FullyQualifiedName string `json:"fullyQualifiedName,omitempty"`
+	Kind               string `json:"kind,omitempty"`
+}
+
+// Output converts the results into a SARIF log with a single run. Each deprecated or deleted
+// Group/Version/Kind (and field) is a rule, and each object using it is a result of the rule
+func (f *sarif) Output(data results.Result) ([]byte, error) {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           "kubepug",
+			Version:        version.GetVersionInfo().GitVersion,
+			InformationURI: kubepugURI,
+			Rules:          make([]sarifRule, 0),
+		}},
+		Results: make([]sarifResult, 0),
+	}
+
+	rules := make(map[string]int)
+	add := func(apis []results.ResultItem, level, status string) {
+		for i := range apis {
+			api := &apis[i]
+			id := resultID(api)
+			index, ok := rules[id]
+			if !ok {
+				index = len(run.Tool.Driver.Rules)
+				rules[id] = index
+				run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, newSARIFRule(id, api, level, status))
+			}
+
+			for _, item := range api.Items {
+				run.Results = append(run.Results, sarifResult{
+					RuleID:    id,
+					RuleIndex: index,
+					Level:     level,
+					Message:   sarifMessage{Text: itemMessage(api, &item, status)},
+					Locations: sarifLocations(&item),
+				})
+			}
+		}
+	}
+	add(data.DeletedAPIs, "error", "deleted")
+	add(data.DeprecatedAPIs, "warning", "deprecated")
+
+	return jsonencoding.MarshalIndent(sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs:    []sarifRun{run},
+	}, "", "  ")
+}
+
+func newSARIFRule(id string, api *results.ResultItem, level, status string) sarifRule {
+	rule := sarifRule{
+		ID:                   id,
+		ShortDescription:     sarifMessage{Text: summary(api, status)},
+		Help:                 sarifMessage{Text: details(api, status)},
+		HelpURI:              "https://kubepug.xyz/status/",
+		DefaultConfiguration: sarifConfiguration{Level: level},
+	}
+	if api.Description != "" {
+		rule.FullDescription = &sarifMessage{Text: api.Description}
+	}
+	return rule
+}
+
+// sarifLocations returns the file the item was read from, and the object itself. Locations that
+// are not files, like Helm releases or API server endpoints, are not valid artifact URIs, so
+// those items only have the object
+func sarifLocations(item *results.Item) []sarifLocation {
+	location := sarifLocation{}
+	if item.File {
+		location.PhysicalLocation = &sarifPhysicalLocation{
+			ArtifactLocation: sarifArtifactLocation{URI: sarifURI(item.Location)},
+		}
+		if item.Line > 0 {
+			location.PhysicalLocation.Region = &sarifRegion{StartLine: item.Line, StartColumn: item.Column}
+		}
+	}
+
+	if item.ObjectName != "" {
+		name := item.ObjectName
+		if item.Namespace != "" {
+			name = item.Namespace + "/" + item.ObjectName
+		}
+		location.LogicalLocations = []sarifLogicalLocation{{
+			Name:               item.ObjectName,
+			FullyQualifiedName: name,
+			Kind:               "object",
+		}}
+	}
+
+	if location.PhysicalLocation == nil && location.LogicalLocations == nil {
+		return nil
+	}
+	return []sarifLocation{location}
+}
+
+// sarifURI converts a location into an URI. Relative paths are kept relative, so they can be
+// resolved against the root of the repository being scanned
+func sarifURI(location string) string {
+	if filepath.IsAbs(location) {
+		return (&url.URL{Scheme: "file", Path: filepath.ToSlash(location)}).String()
+	}
+	return filePath(location)
+}
@@ -0,0 +1,118 @@
+package formatter
+
+import (
+	jsonencoding "encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	apis "github.com/kubepug/kubepug/pkg/apis/v1alpha1"
+	"github.com/kubepug/kubepug/pkg/results"
+)
+
+func TestSARIFOutput(t *testing.T) {
+	f := &sarif{}
+
+	out, err := f.Output(results.Result{
+		DeprecatedAPIs: []results.ResultItem{
+			{
+				Group: "", Version: "v1", Kind: "Pod", Field: "spec.serviceAccount", K8sVersion: "1.1",
+				Items: []results.Item{{Scope: "OBJECT", ObjectName: "debug", Namespace: "default"}},
+			},
+		},
+		DeletedAPIs: []results.ResultItem{
+			{
+				Group: "extensions", Version: "v1beta1", Kind: "Ingress", K8sVersion: "1.22",
+				Description: "Ingress is a collection of rules.",
+				Replacement: &apis.GroupVersionKind{Group: "networking.k8s.io", Version: "v1", Kind: "Ingress"},
+				Items: []results.Item{
+					{Scope: "OBJECT", ObjectName: "web", Namespace: "apps", Location: "./manifests/ingress.yaml", Line: 12, Column: 1, File: true},
+					{Scope: "OBJECT", ObjectName: "api", Namespace: "apps", Location: "/abs/ingress.yaml", File: true},
+					{Scope: "OBJECT", ObjectName: "app", Namespace: "default", Location: "helm:default/app@1"},
+					results.RequestedItem("ingresses", "apiserver:/metrics"),
+					results.CallerItem("alice", "kubectl/v1.15.0", 3),
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	var log sarifLog
+	require.NoError(t, jsonencoding.Unmarshal(out, &log))
+	require.Equal(t, "2.1.0", log.Version)
+	require.Len(t, log.Runs, 1)
+
+	run := log.Runs[0]
+	require.Equal(t, "kubepug", run.Tool.Driver.Name)
+	require.Equal(t, []sarifRule{
+		{
+			ID:                   "extensions/v1beta1/Ingress",
+			ShortDescription:     sarifMessage{Text: "extensions/v1beta1 Ingress is deleted since Kubernetes 1.22"},
+			FullDescription:      &sarifMessage{Text: "Ingress is a collection of rules."},
+			Help:                 sarifMessage{Text: "extensions/v1beta1 Ingress is deleted since Kubernetes 1.22\nIngress is a collection of rules.\nReplacement: networking.k8s.io/v1/Ingress"},
+			HelpURI:              "https://kubepug.xyz/status/",
+			DefaultConfiguration: sarifConfiguration{Level: "error"},
+		},
+		{
+			ID:                   "v1/Pod/spec.serviceAccount",
+			ShortDescription:     sarifMessage{Text: "field spec.serviceAccount of v1 Pod is deprecated since Kubernetes 1.1"},
+			Help:                 sarifMessage{Text: "field spec.serviceAccount of v1 Pod is deprecated since Kubernetes 1.1"},
+			HelpURI:              "https://kubepug.xyz/status/",
+			DefaultConfiguration: sarifConfiguration{Level: "warning"},
+		},
+	}, run.Tool.Driver.Rules)
+
+	require.Equal(t, []sarifResult{
+		{
+			RuleID: "extensions/v1beta1/Ingress", RuleIndex: 0, Level: "error",
+			Message: sarifMessage{Text: "Ingress apps/web uses extensions/v1beta1 Ingress, which is deleted. It should be migrated to networking.k8s.io/v1/Ingress"},
+			Locations: []sarifLocation{{
+				PhysicalLocation: &sarifPhysicalLocation{
+					ArtifactLocation: sarifArtifactLocation{URI: "manifests/ingress.yaml"},
+					Region:           &sarifRegion{StartLine: 12, StartColumn: 1},
+				},
+				LogicalLocations: []sarifLogicalLocation{{Name: "web", FullyQualifiedName: "apps/web", Kind: "object"}},
+			}},
+		},
+		{
+			RuleID: "extensions/v1beta1/Ingress", RuleIndex: 0, Level: "error",
+			Message: sarifMessage{Text: "Ingress apps/api uses extensions/v1beta1 Ingress, which is deleted. It should be migrated to networking.k8s.io/v1/Ingress"},
+			Locations: []sarifLocation{{
+				PhysicalLocation: &sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: "file:///abs/ingress.yaml"}},
+				LogicalLocations: []sarifLogicalLocation{{Name: "api", FullyQualifiedName: "apps/api", Kind: "object"}},
+			}},
+		},
+		{
+			RuleID: "extensions/v1beta1/Ingress", RuleIndex: 0, Level: "error",
+			Message: sarifMessage{Text: "Ingress default/app uses extensions/v1beta1 Ingress, which is deleted. It should be migrated to networking.k8s.io/v1/Ingress"},
+			Locations: []sarifLocation{{
+				LogicalLocations: []sarifLogicalLocation{{Name: "app", FullyQualifiedName: "default/app", Kind: "object"}},
+			}},
+		},
+		{
+			RuleID: "extensions/v1beta1/Ingress", RuleIndex: 0, Level: "error",
+			Message: sarifMessage{Text: "Requests to ingresses use extensions/v1beta1 Ingress, which is deleted. It should be migrated to networking.k8s.io/v1/Ingress"},
+			Locations: []sarifLocation{{
+				LogicalLocations: []sarifLogicalLocation{{Name: "ingresses", FullyQualifiedName: "ingresses", Kind: "object"}},
+			}},
+		},
+		{
+			RuleID: "extensions/v1beta1/Ingress", RuleIndex: 0, Level: "error",
+			Message: sarifMessage{Text: "User alice uses extensions/v1beta1 Ingress, which is deleted. It should be migrated to networking.k8s.io/v1/Ingress"},
+		},
+		{
+			RuleID: "v1/Pod/spec.serviceAccount", RuleIndex: 1, Level: "warning",
+			Message: sarifMessage{Text: "Pod default/debug uses field spec.serviceAccount of v1 Pod, which is deprecated"},
+			Locations: []sarifLocation{{
+				LogicalLocations: []sarifLogicalLocation{{Name: "debug", FullyQualifiedName: "default/debug", Kind: "object"}},
+			}},
+		},
+	}, run.Results)
+}
+
+func TestSARIFOutputEmpty(t *testing.T) {
+	out, err := (&sarif{}).Output(results.Result{})
+	require.NoError(t, err)
+	require.Contains(t, string(out), `"results": []`)
+	require.Contains(t, string(out), `"rules": []`)
+}
@@ -2,7 +2,9 @@ package formatter
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
+	"text/tabwriter"
 
 	"github.com/fatih/color"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -41,14 +43,14 @@ func (f *stdout) Output(data results.Result) ([]byte, error) {
 		s.add(resourceColor("RESULTS"), ":\n", resourceColor("Deprecated APIs"), ":\n")
 
 		for _, api := range data.DeprecatedAPIs {
-			s.add(resourceColor(api.Kind), " found in ", gvColor(api.Group), "/", gvColor(api.Version), "\n")
+			s.addHeader(&api)
 
 			if api.K8sVersion != "" && api.K8sVersion != "unknown" {
 				s.add("\t ├─ ", namespaceColor("Deprecated at:"), " ", api.K8sVersion, "\n")
 			}
 
 			if api.Replacement != nil {
-				s.add("\t ├─ ", namespaceColor("Replacement:"), " ", api.Replacement.Group, "/", api.Replacement.Version, "/", api.Replacement.Kind, "\n")
+				s.add("\t ├─ ", namespaceColor("Replacement:"), " ", api.Replacement.Group, "/", api.Replacement.Version, "/", api.Replacement.Kind, replacementAvailability(api.ReplacementAvailable), "\n")
 			}
 
 			if api.Description != "" {
@@ -64,14 +66,14 @@ func (f *stdout) Output(data results.Result) ([]byte, error) {
 		s.add("\t ", errorColor("APIs REMOVED FROM THE CURRENT VERSION AND SHOULD BE MIGRATED IMMEDIATELY!!"), "\n")
 
 		for _, api := range data.DeletedAPIs {
-			s.add(resourceColor(api.Kind), " found in ", gvColor(api.Group), "/", gvColor(api.Version), "\n")
+			s.addHeader(&api)
 
 			if api.K8sVersion != "" && api.K8sVersion != "unknown" {
 				s.add("\t ├─ ", namespaceColor("Deleted at:"), " ", api.K8sVersion, "\n")
 			}
 
 			if api.Replacement != nil {
-				s.add("\t ├─ ", namespaceColor("Replacement:"), " ", api.Replacement.Group, "/", api.Replacement.Version, "/", api.Replacement.Kind, "\n")
+				s.add("\t ├─ ", namespaceColor("Replacement:"), " ", api.Replacement.Group, "/", api.Replacement.Version, "/", api.Replacement.Kind, replacementAvailability(api.ReplacementAvailable), "\n")
 			}
 
 			if api.Description != "" {
@@ -82,7 +84,28 @@ func (f *stdout) Output(data results.Result) ([]byte, error) {
 		}
 	}
 
-	if len(data.DeletedAPIs) == 0 && len(data.DeprecatedAPIs) == 0 {
+	if len(data.StoredVersions) > 0 {
+		s.add("\n", resourceColor("CRDs Storing Versions To Be Migrated"), ":\n")
+		s.add("\t ", errorColor("OBJECTS MUST BE MIGRATED AND THE VERSION PRUNED FROM status.storedVersions BEFORE IT IS REMOVED!!"), "\n")
+
+		for _, stored := range data.StoredVersions {
+			s.add(resourceColor(stored.CRD), " stores ", gvColor(stored.Group), "/", gvColor(stored.Version), " that is ", stored.Reason, "\n")
+			if stored.StorageVersion != "" {
+				s.add("\t ├─ ", namespaceColor("Storage version:"), " ", stored.StorageVersion, "\n")
+			}
+			if stored.Cluster != "" {
+				s.add("\t ├─ ", namespaceColor("Cluster:"), " ", stored.Cluster, "\n")
+			}
+		}
+		s.add("\n")
+	}
+
+	if len(data.Clusters) > 0 {
+		s.add("\n", resourceColor("Clusters"), ":\n")
+		s.addClusters(data.Clusters)
+	}
+
+	if len(data.DeletedAPIs) == 0 && len(data.DeprecatedAPIs) == 0 && len(data.StoredVersions) == 0 {
 		s.add("\nNo deprecated or deleted APIs found")
 	}
 
@@ -96,6 +119,18 @@ func (f *stdout) Output(data results.Result) ([]byte, error) {
 	return []byte(out), nil
 }
 
+// replacementAvailability tells if the replacement can be used on the current version, when known
+func replacementAvailability(available *bool) string {
+	switch {
+	case available == nil:
+		return ""
+	case *available:
+		return " (available on the current version)"
+	default:
+		return " (not available on the current version)"
+	}
+}
+
 // sliceBuilder is a String Builder that accepts any number of strings at once for ergonomics.
 type sliceBuilder struct {
 	strings.Builder
@@ -108,11 +143,57 @@ func (b *sliceBuilder) add(strs ...string) {
 	}
 }
 
+// addHeader adds the line identifying the deprecated API or, for deprecated fields, the field and its API
+func (b *sliceBuilder) addHeader(api *results.ResultItem) {
+	if api.Field != "" {
+		b.add(resourceColor("Field"), " ", api.Field, " of ", resourceColor(api.Kind), " found in ", gvColor(api.Group), "/", gvColor(api.Version), "\n")
+		return
+	}
+	kind := api.Kind
+	if kind == "" {
+		// The kind is not known for some of the requested APIs, which are identified by the resource on their items
+		kind = "Resources"
+	}
+	b.add(resourceColor(kind), " found in ", gvColor(api.Group), "/", gvColor(api.Version), "\n")
+}
+
+// addClusters adds a table summarizing the findings of each cluster
+func (b *sliceBuilder) addClusters(clusters []results.ClusterSummary) {
+	w := tabwriter.NewWriter(b, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "CLUSTER\tDEPRECATED\tDELETED\tSTORED VERSIONS\tSTATUS")
+	for _, cluster := range clusters {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%s\n", cluster.Name, cluster.DeprecatedAPIs, cluster.DeletedAPIs, cluster.StoredVersions, clusterStatus(cluster))
+	}
+	// tabwriter errors come from the underlying writer, and strings.Builder never fails
+	_ = w.Flush()
+}
+
 func (b *sliceBuilder) addItems(items []results.Item) {
 	for _, i := range items {
 		var fileLocation string
 		if i.Location != "" {
-			fileLocation = fmt.Sprintf("%s %s", locationColor("location:"), i.Location)
+			fileLocation = fmt.Sprintf("%s %s", locationColor("location:"), i.FileLocation())
+		}
+
+		if i.FieldManager != "" {
+			fileLocation = strings.TrimSpace(fmt.Sprintf("%s %s %s", fileLocation, locationColor("manager:"), i.FieldManager))
+		}
+
+		if i.Rule != "" {
+			fileLocation = strings.TrimSpace(fmt.Sprintf("%s %s %s", fileLocation, locationColor("rule:"), i.Rule))
+		}
+
+		if i.Cluster != "" {
+			fileLocation = strings.TrimSpace(fmt.Sprintf("%s %s %s", fileLocation, locationColor("cluster:"), i.Cluster))
+		}
+
+		if i.Scope == "CALLER" {
+			userAgent := i.UserAgent
+			if userAgent == "" {
+				userAgent = "unknown"
+			}
+			b.add("\t\t-> ", globalColor(i.Scope), ": ", i.User, " ", namespaceColor("user-agent:"), " ", userAgent, " ", namespaceColor("calls:"), " ", strconv.Itoa(i.Calls), "\n")
+			continue
 		}
 
 		if i.Scope == "OBJECT" {
@@ -4,6 +4,10 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"k8s.io/utils/ptr"
+
+	apis "github.com/kubepug/kubepug/pkg/apis/v1alpha1"
+	"github.com/kubepug/kubepug/pkg/results"
 )
 
 //nolint:stylecheck
@@ -30,3 +34,128 @@ func TestStoutOutput(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, expected, string(out))
 }
+
+func TestStdoutOutputItemDetails(t *testing.T) {
+	f := &stdout{plain: true}
+
+	out, err := f.Output(results.Result{
+		DeletedAPIs: []results.ResultItem{
+			{
+				Group:   "extensions",
+				Version: "v1beta1",
+				Kind:    "DaemonSet",
+				Items: []results.Item{
+					results.CallerItem("alice", "kubectl/v1.15.0", 3),
+					results.CallerItem("system:anonymous", "", 1),
+					{Scope: "OBJECT", ObjectName: "app", Namespace: "apps", FieldManager: "old-deployer"},
+					{Scope: "GLOBAL", ObjectName: "policy", Rule: "ValidatingWebhookConfiguration webhooks[0].rules[1]"},
+					{Scope: "OBJECT", ObjectName: "web", Namespace: "apps", Location: "manifests/web.yaml", Document: 2, Line: 12, Column: 1},
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.Contains(t, string(out), "-> CALLER: alice user-agent: kubectl/v1.15.0 calls: 3\n")
+	require.Contains(t, string(out), "-> CALLER: system:anonymous user-agent: unknown calls: 1\n")
+	require.Contains(t, string(out), "-> OBJECT: app namespace: apps manager: old-deployer\n")
+	require.Contains(t, string(out), "-> GLOBAL: policy rule: ValidatingWebhookConfiguration webhooks[0].rules[1]\n")
+	require.Contains(t, string(out), "-> OBJECT: web namespace: apps location: manifests/web.yaml:12:1\n")
+}
+
+func TestStdoutOutputStoredVersions(t *testing.T) {
+	f := &stdout{plain: true}
+
+	out, err := f.Output(results.Result{
+		StoredVersions: []results.StoredVersionItem{
+			{CRD: "widgets.example.com", Group: "example.com", Kind: "Widget", Version: "v1beta1", StorageVersion: "v1", Reason: "deprecated"},
+		},
+	})
+	require.NoError(t, err)
+	require.Contains(t, string(out), "CRDs Storing Versions To Be Migrated:\n")
+	require.Contains(t, string(out), "widgets.example.com stores example.com/v1beta1 that is deprecated\n ├─ Storage version: v1\n")
+	require.NotContains(t, string(out), "No deprecated or deleted APIs found")
+}
+
+func TestStdoutOutputClusters(t *testing.T) {
+	f := &stdout{plain: true}
+
+	out, err := f.Output(results.Result{
+		DeletedAPIs: []results.ResultItem{
+			{
+				Group: "extensions", Version: "v1beta1", Kind: "Ingress",
+				Items: []results.Item{{Scope: "OBJECT", ObjectName: "web", Namespace: "apps", Cluster: "prod"}},
+			},
+		},
+		Clusters: []results.ClusterSummary{
+			{Name: "prod", DeletedAPIs: 1},
+			{Name: "staging", Error: "connection refused"},
+		},
+	})
+	require.NoError(t, err)
+	require.Contains(t, string(out), "-> OBJECT: web namespace: apps cluster: prod\n")
+	require.Contains(t, string(out), "Clusters:\n"+
+		"CLUSTER   DEPRECATED   DELETED   STORED VERSIONS   STATUS\n"+
+		"prod      0            1         0                 OK\n"+
+		"staging   0            0         0                 FAILED: connection refused\n")
+}
+
+func TestStdoutOutputReplacementAvailable(t *testing.T) {
+	f := &stdout{plain: true}
+
+	out, err := f.Output(results.Result{
+		DeprecatedAPIs: []results.ResultItem{
+			{
+				Group: "batch", Version: "v1beta1", Kind: "CronJob",
+				Replacement:          &apis.GroupVersionKind{Group: "batch", Version: "v1", Kind: "CronJob"},
+				ReplacementAvailable: ptr.To(true),
+			},
+		},
+		DeletedAPIs: []results.ResultItem{
+			{
+				Group: "extensions", Version: "v1beta1", Kind: "Ingress",
+				Replacement:          &apis.GroupVersionKind{Group: "networking.k8s.io", Version: "v1", Kind: "Ingress"},
+				ReplacementAvailable: ptr.To(false),
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.Contains(t, string(out), "├─ Replacement: batch/v1/CronJob (available on the current version)\n")
+	require.Contains(t, string(out), "├─ Replacement: networking.k8s.io/v1/Ingress (not available on the current version)\n")
+}
+
+func TestStdoutOutputFields(t *testing.T) {
+	f := &stdout{plain: true}
+
+	out, err := f.Output(results.Result{
+		DeprecatedAPIs: []results.ResultItem{
+			{
+				Group: "", Version: "v1", Kind: "Pod", Field: "spec.serviceAccount", K8sVersion: "1.1",
+				Items: []results.Item{{Scope: "OBJECT", ObjectName: "debug", Namespace: "default"}},
+			},
+		},
+		DeletedAPIs: []results.ResultItem{
+			{
+				Group: "", Version: "v1", Kind: "Pod", Field: "metadata.clusterName", K8sVersion: "1.25",
+				Items: []results.Item{{Scope: "OBJECT", ObjectName: "debug", Namespace: "default"}},
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.Contains(t, string(out), "Field spec.serviceAccount of Pod found in /v1\n ├─ Deprecated at: 1.1\n")
+	require.Contains(t, string(out), "Field metadata.clusterName of Pod found in /v1\n ├─ Deleted at: 1.25\n")
+}
+
+func TestStdoutOutputRequestedResources(t *testing.T) {
+	f := &stdout{plain: true}
+
+	out, err := f.Output(results.Result{
+		DeprecatedAPIs: []results.ResultItem{
+			{
+				Group: "example.com", Version: "v1beta1", K8sVersion: "unknown",
+				Items: []results.Item{results.RequestedItem("widgets", "apiserver:/metrics")},
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.Contains(t, string(out), "Resources found in example.com/v1beta1\n-> REQUESTED: widgets location: apiserver:/metrics\n")
+}
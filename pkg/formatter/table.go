@@ -0,0 +1,50 @@
+package formatter
+
+import (
+	"strings"
+	"text/tabwriter"
+
+	"github.com/kubepug/kubepug/pkg/results"
+)
+
+type table struct {
+	rowsConfig
+}
+
+func newTableFormatter(config rowsConfig) Formatter {
+	return &table{
+		rowsConfig: config,
+	}
+}
+
+// Output writes a row with aligned columns for each object affected by a deleted or deprecated API,
+// like the kubectl tables
+func (f *table) Output(data results.Result) ([]byte, error) {
+	rows := f.rows(&data)
+	if len(rows) == 0 {
+		return []byte("No deprecated or deleted APIs found\n"), nil
+	}
+
+	var out strings.Builder
+	w := tabwriter.NewWriter(&out, 0, 0, 3, ' ', 0)
+	if _, err := w.Write([]byte(strings.Join(f.columns, "\t") + "\n")); err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		// Empty cells are shown as <none>, so the columns are still aligned when the output is split by spaces
+		cells := make([]string, 0, len(row))
+		for _, cell := range row {
+			if cell == "" {
+				cell = "<none>"
+			}
+			cells = append(cells, cell)
+		}
+		if _, err := w.Write([]byte(strings.Join(cells, "\t") + "\n")); err != nil {
+			return nil, err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return nil, err
+	}
+	return []byte(out.String()), nil
+}
@@ -0,0 +1,27 @@
+package formatter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubepug/kubepug/pkg/results"
+)
+
+func TestTableOutput(t *testing.T) {
+	f, err := NewFormatterWithConfig("table", Config{Columns: []string{"STATUS", "KIND", "NAMESPACE", "NAME", "LOCATION"}, SortBy: "kind"})
+	require.NoError(t, err)
+
+	out, err := f.Output(junitResult)
+	require.NoError(t, err)
+	require.Equal(t, "STATUS       KIND      NAMESPACE   NAME      LOCATION\n"+
+		"deprecated   CronJob   jobs        cleanup   manifests/cronjob.yaml:3:1\n"+
+		"deleted      Ingress   apps        web       <none>\n"+
+		"deleted      Ingress   <none>      alice     <none>\n", string(out))
+}
+
+func TestTableOutputEmpty(t *testing.T) {
+	out, err := newTableFormatter(rowsConfig{columns: defaultColumns}).Output(results.Result{})
+	require.NoError(t, err)
+	require.Equal(t, "No deprecated or deleted APIs found\n", string(out))
+}
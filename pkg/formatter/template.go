@@ -0,0 +1,92 @@
+package formatter
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/fatih/color"
+
+	apis "github.com/kubepug/kubepug/pkg/apis/v1alpha1"
+	"github.com/kubepug/kubepug/pkg/results"
+)
+
+type templateFormatter struct {
+	tmpl *template.Template
+}
+
+// newTemplateFormatter parses a Go template, passed inline or as the location of a file containing it
+func newTemplateFormatter(tpl string) (Formatter, error) {
+	if tpl == "" {
+		return nil, fmt.Errorf("a template is required by the template formatter")
+	}
+
+	name := "template"
+	if info, err := os.Stat(tpl); err == nil && info.Mode().IsRegular() {
+		content, err := os.ReadFile(tpl)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read template %s: %w", tpl, err)
+		}
+		name = filepath.Base(tpl)
+		tpl = string(content)
+	}
+
+	tmpl, err := template.New(name).Funcs(templateFuncs).Parse(tpl)
+	if err != nil {
+		return nil, fmt.Errorf("invalid template: %w", err)
+	}
+	return &templateFormatter{tmpl: tmpl}, nil
+}
+
+// templateFuncs are the helper functions available to the templates, besides the Go template builtins
+var templateFuncs = template.FuncMap{
+	"red":     color.New(color.FgRed).SprintFunc(),
+	"green":   color.New(color.FgGreen).SprintFunc(),
+	"yellow":  color.New(color.FgYellow).SprintFunc(),
+	"blue":    color.New(color.FgBlue).SprintFunc(),
+	"magenta": color.New(color.FgMagenta).SprintFunc(),
+	"cyan":    color.New(color.FgCyan).SprintFunc(),
+	"bold":    color.New(color.Bold).SprintFunc(),
+	"upper":   strings.ToUpper,
+	"lower":   strings.ToLower,
+	// join is called as {{ join ", " .List }}, or {{ .List | join ", " }}
+	"join": func(sep string, elems []string) string {
+		return strings.Join(elems, sep)
+	},
+	// gvk formats the API of a result, like extensions/v1beta1/Ingress, appending the field for
+	// deprecated fields
+	"gvk": func(api results.ResultItem) string {
+		return resultID(&api)
+	},
+	// apiVersion formats the apiVersion of a result, like apps/v1 or v1 for core APIs
+	"apiVersion": func(api results.ResultItem) string {
+		return apiName(&api)
+	},
+	// replacement formats the replacement of an API, like networking.k8s.io/v1/Ingress, being
+	// empty when there is no replacement
+	"replacement": func(replacement *apis.GroupVersionKind) string {
+		if replacement == nil {
+			return ""
+		}
+		return replacement.Group + "/" + replacement.Version + "/" + replacement.Kind
+	},
+	// names returns the names of the items, like the namespace/name of objects
+	"names": func(items []results.Item) []string {
+		names := make([]string, 0, len(items))
+		for i := range items {
+			names = append(names, itemName(&items[i]))
+		}
+		return names
+	},
+}
+
+func (f *templateFormatter) Output(data results.Result) ([]byte, error) {
+	var out bytes.Buffer
+	if err := f.tmpl.Execute(&out, data); err != nil {
+		return nil, fmt.Errorf("failed to execute template: %w", err)
+	}
+	return out.Bytes(), nil
+}
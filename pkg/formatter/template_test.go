@@ -0,0 +1,80 @@
+package formatter
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubepug/kubepug/pkg/results"
+)
+
+func TestTemplateOutput(t *testing.T) {
+	defer func(noColor bool) { color.NoColor = noColor }(color.NoColor)
+	color.NoColor = true
+
+	tests := []struct {
+		name     string
+		template string
+		want     string
+	}{
+		{
+			name:     "results should be available to the template",
+			template: `{{ range .DeletedAPIs }}{{ gvk . }} -> {{ replacement .Replacement }}: {{ names .Items | join ", " }}{{ end }}`,
+			want:     "extensions/v1beta1/Ingress -> networking.k8s.io/v1/Ingress: apps/web, user alice",
+		},
+		{
+			name:     "helpers should format the results",
+			template: `{{ range .DeprecatedAPIs }}{{ red .Kind }} {{ apiVersion . | upper }} {{ replacement nil }}{{ range .Items }}{{ .FileLocation }}{{ end }}{{ end }}`,
+			want:     "CronJob BATCH/V1BETA1 manifests/cronjob.yaml:3:1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := NewFormatterWithConfig("template", Config{Template: tt.template})
+			require.NoError(t, err)
+
+			out, err := f.Output(junitResult)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, string(out))
+		})
+	}
+
+	t.Run("templates should be read from files", func(t *testing.T) {
+		location := filepath.Join(t.TempDir(), "slack.tmpl")
+		require.NoError(t, os.WriteFile(location, []byte(`{{ len .DeletedAPIs }} deleted APIs`), 0o600))
+
+		f, err := NewFormatterWithConfig("template", Config{Template: location})
+		require.NoError(t, err)
+
+		out, err := f.Output(junitResult)
+		require.NoError(t, err)
+		require.Equal(t, "1 deleted APIs", string(out))
+	})
+
+	t.Run("gvk should append the field of deprecated fields", func(t *testing.T) {
+		f, err := NewFormatterWithConfig("template", Config{Template: `{{ range .DeprecatedAPIs }}{{ gvk . }}{{ end }}`})
+		require.NoError(t, err)
+
+		out, err := f.Output(results.Result{
+			DeprecatedAPIs: []results.ResultItem{{Version: "v1", Kind: "Pod", Field: "spec.serviceAccount"}},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "v1/Pod/spec.serviceAccount", string(out))
+	})
+
+	t.Run("invalid templates should fail", func(t *testing.T) {
+		_, err := NewFormatterWithConfig("template", Config{Template: `{{ range .DeletedAPIs }}`})
+		require.ErrorContains(t, err, "invalid template")
+	})
+
+	t.Run("templates failing to execute should fail", func(t *testing.T) {
+		f, err := NewFormatterWithConfig("template", Config{Template: `{{ .Missing }}`})
+		require.NoError(t, err)
+
+		_, err = f.Output(junitResult)
+		require.ErrorContains(t, err, "failed to execute template")
+	})
+}
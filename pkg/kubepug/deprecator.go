@@ -1,23 +1,53 @@
 package kubepug
 
 import (
+	"context"
+
 	"github.com/kubepug/kubepug/pkg/results"
 )
 
 // Deprecator implements an interface for reading some sort of Input and comparing against the
 // map of Kubernetes APIs to check if there's some Deprecated or Deleted
 type Deprecator interface {
-	GetDeprecations() (deprecated []results.ResultItem, deleted []results.ResultItem, err error)
+	GetDeprecations(ctx context.Context) (deprecated []results.ResultItem, deleted []results.ResultItem, err error)
+}
+
+// StoredVersionsChecker is implemented by the Inputs able to verify if CustomResourceDefinitions
+// still store objects on versions that should be migrated
+type StoredVersionsChecker interface {
+	GetStoredVersions(ctx context.Context) ([]results.StoredVersionItem, error)
 }
 
-// GetDeprecations returns the results of the comparison between the Input and the APIs
-func GetDeprecations(d Deprecator) (result results.Result, err error) {
-	deprecated, deleted, err := d.GetDeprecations()
-	if err != nil {
-		return result, err
+// GetDeprecations returns the results of the comparison between the Inputs and the APIs.
+// When more than one Input is used, the findings of the same API are merged together
+func GetDeprecations(ctx context.Context, deprecators ...Deprecator) (result results.Result, err error) {
+	allDeprecated := make([][]results.ResultItem, 0, len(deprecators))
+	allDeleted := make([][]results.ResultItem, 0, len(deprecators))
+	for _, d := range deprecators {
+		deprecated, deleted, err := d.GetDeprecations(ctx)
+		if err != nil {
+			return result, err
+		}
+		allDeprecated = append(allDeprecated, deprecated)
+		allDeleted = append(allDeleted, deleted)
+
+		if checker, ok := d.(StoredVersionsChecker); ok {
+			storedVersions, err := checker.GetStoredVersions(ctx)
+			if err != nil {
+				return result, err
+			}
+			result.StoredVersions = append(result.StoredVersions, storedVersions...)
+		}
 	}
-	result.DeprecatedAPIs = deprecated
-	result.DeletedAPIs = deleted
+
+	if len(deprecators) == 1 {
+		result.DeprecatedAPIs = allDeprecated[0]
+		result.DeletedAPIs = allDeleted[0]
+		return result, nil
+	}
+
+	result.DeprecatedAPIs = results.MergeResultItems(allDeprecated...)
+	result.DeletedAPIs = results.MergeResultItems(allDeleted...)
 
 	return result, nil
 }
@@ -1,8 +1,10 @@
 package kubepug
 
 import (
+	"context"
 	"testing"
 
+	"github.com/kubepug/kubepug/pkg/results"
 	mock "github.com/kubepug/kubepug/pkg/store/mock"
 	"github.com/stretchr/testify/require"
 )
@@ -10,7 +12,7 @@ import (
 func TestGetDeprecations(t *testing.T) {
 	t.Run("should return an error", func(t *testing.T) {
 		store := mock.NewMockStore(true, true)
-		result, err := GetDeprecations(store)
+		result, err := GetDeprecations(context.Background(), store)
 		require.Error(t, err)
 		require.Empty(t, result.DeletedAPIs)
 		require.Empty(t, result.DeprecatedAPIs)
@@ -18,9 +20,42 @@ func TestGetDeprecations(t *testing.T) {
 
 	t.Run("should return correctly", func(t *testing.T) {
 		store := mock.NewMockStore(true, false)
-		result, err := GetDeprecations(store)
+		result, err := GetDeprecations(context.Background(), store)
 		require.NoError(t, err)
 		require.Equal(t, mock.DeletedMock, result.DeletedAPIs)
 		require.Equal(t, mock.DeprecatedMock, result.DeprecatedAPIs)
 	})
+
+	t.Run("should merge the results of all the inputs", func(t *testing.T) {
+		result, err := GetDeprecations(context.Background(), mock.NewMockStore(true, false), mock.NewMockStore(true, false))
+		require.NoError(t, err)
+		require.Len(t, result.DeletedAPIs, 1)
+		require.Len(t, result.DeletedAPIs[0].Items, 2)
+		require.Len(t, result.DeprecatedAPIs, 1)
+		require.Len(t, result.DeprecatedAPIs[0].Items, 2)
+	})
+
+	t.Run("should fail if any input fails", func(t *testing.T) {
+		_, err := GetDeprecations(context.Background(), mock.NewMockStore(true, false), mock.NewMockStore(true, true))
+		require.Error(t, err)
+	})
+
+	t.Run("should return the stored versions of the inputs", func(t *testing.T) {
+		checker := &storedVersionsMock{
+			Store: mock.NewMockStore(true, false),
+			items: []results.StoredVersionItem{{CRD: "widgets.example.com", Version: "v1beta1", Reason: "deprecated"}},
+		}
+		result, err := GetDeprecations(context.Background(), checker)
+		require.NoError(t, err)
+		require.Equal(t, checker.items, result.StoredVersions)
+	})
+}
+
+type storedVersionsMock struct {
+	*mock.Store
+	items []results.StoredVersionItem
+}
+
+func (s *storedVersionsMock) GetStoredVersions(_ context.Context) ([]results.StoredVersionItem, error) {
+	return s.items, nil
 }
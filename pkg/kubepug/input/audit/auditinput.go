@@ -0,0 +1,236 @@
+package auditinput
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	json "github.com/goccy/go-json"
+	"github.com/sirupsen/logrus"
+
+	"github.com/kubepug/kubepug/pkg/errors"
+	"github.com/kubepug/kubepug/pkg/results"
+	"github.com/kubepug/kubepug/pkg/store"
+	"github.com/kubepug/kubepug/pkg/utils"
+)
+
+const auditEventKind = "Event"
+
+// auditEvent contains the fields of an audit.k8s.io/v1 Event that are relevant to find
+// the callers of deprecated APIs
+type auditEvent struct {
+	Kind    string `json:"kind"`
+	AuditID string `json:"auditID"`
+	User    struct {
+		Username string `json:"username"`
+	} `json:"user"`
+	UserAgent string `json:"userAgent"`
+	ObjectRef *struct {
+		Resource   string `json:"resource"`
+		APIGroup   string `json:"apiGroup"`
+		APIVersion string `json:"apiVersion"`
+	} `json:"objectRef"`
+}
+
+// APIResource is a Group/Version/Resource called by a client
+type APIResource struct {
+	Group    string
+	Version  string
+	Resource string
+}
+
+// Caller is a client identified by the user and the user agent
+type Caller struct {
+	User      string
+	UserAgent string
+}
+
+// AuditItems contains how many times each caller requested each API resource
+type AuditItems map[APIResource]map[Caller]int
+
+// AuditInput defines a struct that will be used when comparing the APIs called on the audit logs
+type AuditInput struct {
+	AuditItems AuditItems
+	Store      store.DefinitionStorer
+	// We will have a IncludeGroup and a IgnoreGroup configs to tune false positives and false negatives
+	// If there is an IncludeGroup, only the resources on this group will be parsed
+	IncludePrefixGroup []string
+	// If an API is inside the IgnoreGroup it will be bypassed
+	IgnoreExactGroup []string
+}
+
+// NewAuditInput returns the struct AuditInput already populated with the API calls
+// found on the audit log. Location can be "-" to read the audit log from STDIN
+func NewAuditInput(location string, storer store.DefinitionStorer) (*AuditInput, error) {
+	if _, ok := storer.(store.KindResolver); !ok {
+		return nil, fmt.Errorf("the store is not able to find the kinds of the audited resources")
+	}
+
+	auditItems, err := GetAuditItems(location)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AuditInput{
+		AuditItems:         auditItems,
+		Store:              storer,
+		IgnoreExactGroup:   store.DefaultIgnoreGroups(),
+		IncludePrefixGroup: store.IncludeGroups(storer, []string{".k8s.io"}),
+	}, nil
+}
+
+// GetAuditItems reads an audit log file, or STDIN when location is "-", and
+// counts the calls made by each client to each API resource
+func GetAuditItems(location string) (AuditItems, error) {
+	var reader io.Reader
+	if location == "-" {
+		reader = os.Stdin
+	} else {
+		file, err := os.Open(location)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open audit log %s: %w", location, err)
+		}
+		defer file.Close()
+		reader = file
+	}
+
+	return ReadAuditItems(reader)
+}
+
+// ReadAuditItems reads audit events, one JSON object per line, and counts the calls made by
+// each client to each API resource. Events of the same request, logged on different stages,
+// are counted only once
+func ReadAuditItems(reader io.Reader) (AuditItems, error) {
+	auditItems := make(AuditItems)
+	seen := make(map[string]struct{})
+
+	buf := bufio.NewReader(reader)
+	lineNumber := 0
+	for {
+		// Audit events containing the request and response objects can be bigger than
+		// the bufio.Scanner limits, so the lines are read as a whole
+		line, err := buf.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return nil, fmt.Errorf("failed to read audit log: %w", err)
+		}
+		lineNumber++
+
+		if trimmed := bytes.TrimSpace(line); len(trimmed) > 0 {
+			auditItems.addEvent(trimmed, lineNumber, seen)
+		}
+
+		if err == io.EOF {
+			break
+		}
+	}
+
+	return auditItems, nil
+}
+
+func (a AuditItems) addEvent(line []byte, lineNumber int, seen map[string]struct{}) {
+	event := auditEvent{}
+	if err := json.Unmarshal(line, &event); err != nil {
+		logrus.Warningf("failed to parse audit event on line %d, skipping: %s", lineNumber, err)
+		return
+	}
+
+	// Non resource requests (like /healthz) don't have an objectRef
+	if event.Kind != auditEventKind || event.ObjectRef == nil || event.ObjectRef.Resource == "" || event.ObjectRef.APIVersion == "" {
+		return
+	}
+
+	if event.AuditID != "" {
+		if _, ok := seen[event.AuditID]; ok {
+			return
+		}
+		seen[event.AuditID] = struct{}{}
+	}
+
+	resource := APIResource{
+		Group:    event.ObjectRef.APIGroup,
+		Version:  event.ObjectRef.APIVersion,
+		Resource: event.ObjectRef.Resource,
+	}
+	if _, ok := a[resource]; !ok {
+		a[resource] = make(map[Caller]int)
+	}
+	a[resource][Caller{User: event.User.Username, UserAgent: event.UserAgent}]++
+}
+
+// GetDeprecations compares the API resources called on the audit logs with Kubepug store
+// returning the set of Deprecated results
+func (a *AuditInput) GetDeprecations(ctx context.Context) (deprecated, deleted []results.ResultItem, err error) {
+	resolver, ok := a.Store.(store.KindResolver)
+	if !ok {
+		return nil, nil, fmt.Errorf("the store is not able to find the kinds of the audited resources")
+	}
+
+	for resource, callers := range a.AuditItems {
+		if !utils.ShouldParse(resource.Group, a.IgnoreExactGroup, a.IncludePrefixGroup) {
+			continue
+		}
+
+		kind, err := resolver.GetKindForResource(ctx, resource.Group, resource.Version, resource.Resource)
+		if err != nil {
+			if errors.IsErrAPINotFound(err) {
+				logrus.Debugf("unable to find the kind of %s/%s/%s, skipping", resource.Group, resource.Version, resource.Resource)
+				continue
+			}
+			return deprecated, deleted, err
+		}
+
+		apiDef, err := a.Store.GetAPIDefinition(ctx, resource.Group, resource.Version, kind)
+		if err != nil {
+			if !errors.IsErrAPINotFound(err) {
+				return deprecated, deleted, err
+			}
+		}
+
+		if apiDef.DeletedVersion == "" && apiDef.DeprecationVersion == "" {
+			continue
+		}
+
+		result := results.CreateItem(resource.Group, resource.Version, kind, callerItems(callers))
+		result.Description = apiDef.Description
+
+		if apiDef.Replacement != nil {
+			result.Replacement = apiDef.Replacement
+			result.ReplacementAvailable = apiDef.ReplacementAvailable
+		}
+
+		result.K8sVersion = apiDef.DeprecationVersion
+
+		if apiDef.DeletedVersion != "" {
+			result.K8sVersion = apiDef.DeletedVersion
+			deleted = append(deleted, result)
+			continue
+		}
+		deprecated = append(deprecated, result)
+	}
+
+	return deprecated, deleted, nil
+}
+
+// callerItems converts the callers to result Items, the ones with more calls first
+func callerItems(callers map[Caller]int) []results.Item {
+	items := make([]results.Item, 0, len(callers))
+	for caller, calls := range callers {
+		items = append(items, results.CallerItem(caller.User, caller.UserAgent, calls))
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Calls != items[j].Calls {
+			return items[i].Calls > items[j].Calls
+		}
+		if items[i].User != items[j].User {
+			return items[i].User < items[j].User
+		}
+		return items[i].UserAgent < items[j].UserAgent
+	})
+
+	return items
+}
@@ -0,0 +1,70 @@
+package auditinput
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubepug/kubepug/pkg/results"
+	"github.com/kubepug/kubepug/pkg/store/generatedstore"
+	"github.com/kubepug/kubepug/pkg/store/mock"
+)
+
+const auditLog = "../../../../test/testdata/audit/audit.log"
+
+func TestGetAuditItems(t *testing.T) {
+	t.Run("non existing file should fail", func(t *testing.T) {
+		_, err := GetAuditItems("/tmp/xpto/non-existing.log")
+		require.ErrorContains(t, err, "failed to open audit log")
+	})
+
+	t.Run("audit log should be parsed", func(t *testing.T) {
+		items, err := GetAuditItems(auditLog)
+		require.NoError(t, err)
+		require.Len(t, items, 3)
+
+		daemonsets := items[APIResource{Group: "extensions", Version: "v1beta1", Resource: "daemonsets"}]
+		require.Equal(t, map[Caller]int{
+			{User: "system:serviceaccount:ci:deployer", UserAgent: "old-deployer/1.0"}: 2,
+			{User: "alice", UserAgent: "kubectl/v1.15.0"}:                              1,
+		}, daemonsets)
+	})
+
+	t.Run("last line without a line break should be parsed", func(t *testing.T) {
+		items, err := ReadAuditItems(strings.NewReader(`{"kind":"Event","auditID":"x","user":{"username":"alice"},"objectRef":{"resource":"pods","apiVersion":"v1"}}`))
+		require.NoError(t, err)
+		require.Equal(t, 1, items[APIResource{Version: "v1", Resource: "pods"}][Caller{User: "alice"}])
+	})
+}
+
+func TestGetDeprecations(t *testing.T) {
+	storer, err := generatedstore.NewGeneratedStoreFromBytes([]byte(mock.MockValidData), generatedstore.StoreConfig{})
+	require.NoError(t, err)
+
+	input, err := NewAuditInput(auditLog, storer)
+	require.NoError(t, err)
+
+	deprecated, deleted, err := input.GetDeprecations(context.Background())
+	require.NoError(t, err)
+	require.Empty(t, deprecated)
+	require.Len(t, deleted, 2)
+
+	var daemonset results.ResultItem
+	for _, d := range deleted {
+		if d.Kind == "DaemonSet" {
+			daemonset = d
+		}
+	}
+	require.Equal(t, "extensions", daemonset.Group)
+	require.Equal(t, []results.Item{
+		results.CallerItem("system:serviceaccount:ci:deployer", "old-deployer/1.0", 2),
+		results.CallerItem("alice", "kubectl/v1.15.0", 1),
+	}, daemonset.Items)
+}
+
+func TestNewAuditInputWithoutKindResolver(t *testing.T) {
+	_, err := NewAuditInput(auditLog, nil)
+	require.ErrorContains(t, err, "not able to find the kinds")
+}
@@ -0,0 +1,5 @@
+// Package auditinput contains the methods used to get the Deprecated APIs
+// that were called, reading Kubernetes audit logs
+package auditinput
+
+// import "github.com/kubepug/kubepug/pkg/kubepug/input/audit"
@@ -20,41 +20,81 @@ type FileInput struct {
 	IncludePrefixGroup []string
 	// If an API is inside the IgnoreGroup it will be bypassed
 	IgnoreExactGroup []string
+	// Fields enables the verification of deprecated and deleted fields on the body of the objects. The
+	// body is only kept for the objects added after it is enabled, with AddLocation, AddManifests or AddObject
+	Fields bool
+	// bodies contains the body of the objects of FileItems, when Fields is enabled
+	bodies objectBodies
 }
 
 // NewFileInput returns the struct FileInput already populated
 func NewFileInput(location string, storer store.DefinitionStorer) (fileInput *FileInput, err error) {
-	fileInput = &FileInput{}
-	fileitems, err := GetFileItems(location)
+	return NewFileInputWithConfig(location, WalkConfig{}, storer)
+}
+
+// NewFileInputWithConfig returns the struct FileInput already populated, traversing
+// the input location as defined on the WalkConfig
+func NewFileInputWithConfig(location string, config WalkConfig, storer store.DefinitionStorer) (fileInput *FileInput, err error) {
+	fileitems, err := GetFileItemsWithConfig(location, config)
 	if err != nil {
-		return fileInput, err
+		return &FileInput{}, err
 	}
 
-	fileInput.Store = storer
-	fileInput.FileItems = fileitems
-	// The groups below are: externaldns (not core), anything on x-k8s.io, internal flowcontrol and the autoscaling group that is actually a CRD (the real autoscaling is just autoscaling/version)
-	fileInput.IgnoreExactGroup = []string{"externaldns.k8s.io", "x-k8s.io", "flowcontrol.apiserver.k8s.io", "autoscaling.k8s.io"}
-	fileInput.IncludePrefixGroup = []string{".k8s.io"}
+	return NewFileInputFromItems(fileitems, storer), nil
+}
 
-	return fileInput, nil
+// NewFileInputFromItems returns the struct FileInput populated with FileItems that were
+// already parsed from some other source
+func NewFileInputFromItems(fileItems FileItems, storer store.DefinitionStorer) *FileInput {
+	return &FileInput{
+		Store:              storer,
+		FileItems:          fileItems,
+		IgnoreExactGroup:   store.DefaultIgnoreGroups(),
+		IncludePrefixGroup: store.IncludeGroups(storer, []string{".k8s.io"}),
+	}
+}
+
+// AddLocation inserts the objects of the files on location into the FileItems, traversing the
+// location as defined on the WalkConfig
+func (f *FileInput) AddLocation(location string, config WalkConfig) error {
+	return f.items().addLocation(location, config, f.objectBodies())
+}
+
+// AddManifests inserts the objects of a set of manifests into the FileItems, as FileItems.AddManifests
+func (f *FileInput) AddManifests(manifests []byte, location string) {
+	f.items().addManifests(manifests, results.Item{Location: location}, false, f.objectBodies())
+}
+
+// AddObject inserts an object into the FileItems, keeping its body when Fields is enabled. It returns
+// false if the object cannot be indexed because apiVersion or kind are empty
+func (f *FileInput) AddObject(apiVersion, kind string, item results.Item, body map[string]interface{}) bool {
+	return f.items().addItem(apiVersion, kind, item, body, f.objectBodies())
+}
+
+func (f *FileInput) items() FileItems {
+	if f.FileItems == nil {
+		f.FileItems = make(FileItems)
+	}
+	return f.FileItems
+}
+
+// objectBodies returns where the body of the objects is kept, or nil when Fields is disabled
+func (f *FileInput) objectBodies() objectBodies {
+	if !f.Fields {
+		return nil
+	}
+	if f.bodies == nil {
+		f.bodies = make(objectBodies)
+	}
+	return f.bodies
 }
 
 // GetDeprecations retrieves the map of FileItems and compares with Kubepug store
 // returning the set of Deprecated results
-func (f *FileInput) GetDeprecations() (deprecated, deleted []results.ResultItem, err error) {
+func (f *FileInput) GetDeprecations(ctx context.Context) (deprecated, deleted []results.ResultItem, err error) {
 	for key, item := range f.FileItems {
-		gvk := strings.Split(key, "/")
-		var group, version, kind string
-		switch len(gvk) {
-		// This is a CoreAPI, like v1/Namespace
-		case 2:
-			version = gvk[0]
-			kind = gvk[1]
-		case 3:
-			group = gvk[0]
-			version = gvk[1]
-			kind = gvk[2]
-		default:
+		group, version, kind, ok := splitKey(key)
+		if !ok {
 			logrus.Info("unknown API type, skipping")
 			continue
 		}
@@ -63,7 +103,7 @@ func (f *FileInput) GetDeprecations() (deprecated, deleted []results.ResultItem,
 			continue
 		}
 
-		apiDef, err := f.Store.GetAPIDefinition(context.Background(), group, version, kind)
+		apiDef, err := f.Store.GetAPIDefinition(ctx, group, version, kind)
 		if err != nil {
 			if !errors.IsErrAPINotFound(err) {
 				return deprecated, deleted, err
@@ -79,6 +119,7 @@ func (f *FileInput) GetDeprecations() (deprecated, deleted []results.ResultItem,
 
 		if apiDef.Replacement != nil {
 			result.Replacement = apiDef.Replacement
+			result.ReplacementAvailable = apiDef.ReplacementAvailable
 		}
 
 		result.K8sVersion = apiDef.DeprecationVersion
@@ -91,5 +132,93 @@ func (f *FileInput) GetDeprecations() (deprecated, deleted []results.ResultItem,
 		deprecated = append(deprecated, result)
 	}
 
+	if f.Fields {
+		deprecatedFields, deletedFields, err := f.GetFieldDeprecations(ctx)
+		if err != nil {
+			return deprecated, deleted, err
+		}
+		deprecated = append(deprecated, deprecatedFields...)
+		deleted = append(deleted, deletedFields...)
+	}
+
 	return deprecated, deleted, nil
 }
+
+// GetFieldDeprecations walks the body of the objects added while Fields was enabled, returning a result
+// for each deprecated or deleted field in use. Stores that don't know about fields return no results
+func (f *FileInput) GetFieldDeprecations(ctx context.Context) (deprecated, deleted []results.ResultItem, err error) {
+	lister, ok := f.Store.(store.FieldLister)
+	if !ok {
+		logrus.Debug("the store does not contain deprecated fields, skipping")
+		return nil, nil, nil
+	}
+
+	for key, items := range f.FileItems {
+		group, version, kind, ok := splitKey(key)
+		if !ok || !utils.ShouldParse(group, f.IgnoreExactGroup, f.IncludePrefixGroup) {
+			continue
+		}
+
+		fields, err := lister.GetDeprecatedFields(ctx, group, version, kind)
+		if err != nil {
+			return deprecated, deleted, err
+		}
+
+		for _, field := range fields {
+			var found []results.Item
+			for i := range items {
+				if utils.HasField(f.bodies.body(key, i), field.Path) {
+					found = append(found, items[i])
+				}
+			}
+			if len(found) == 0 {
+				continue
+			}
+
+			result := results.CreateItem(group, version, kind, found)
+			result.Field = fieldPath(field.Path)
+			result.Description = field.Description
+			result.K8sVersion = field.DeprecationVersion
+
+			if field.DeletedVersion != "" {
+				result.K8sVersion = field.DeletedVersion
+				deleted = append(deleted, result)
+				continue
+			}
+			deprecated = append(deprecated, result)
+		}
+	}
+
+	return deprecated, deleted, nil
+}
+
+// fieldPath joins the path of a field with dots. Elements containing dots, like annotation
+// keys, are enclosed in brackets so the path is not ambiguous
+func fieldPath(path []string) string {
+	var sb strings.Builder
+	for i, element := range path {
+		if strings.Contains(element, ".") {
+			sb.WriteString("[" + element + "]")
+			continue
+		}
+		if i > 0 {
+			sb.WriteString(".")
+		}
+		sb.WriteString(element)
+	}
+	return sb.String()
+}
+
+// splitKey returns the group, version and kind of a FileItems key
+func splitKey(key string) (group, version, kind string, ok bool) {
+	gvk := strings.Split(key, "/")
+	switch len(gvk) {
+	// This is a CoreAPI, like v1/Namespace
+	case 2:
+		return "", gvk[0], gvk[1], true
+	case 3:
+		return gvk[0], gvk[1], gvk[2], true
+	default:
+		return "", "", "", false
+	}
+}
@@ -1,3 +1,139 @@
 package fileinput
 
-// TODO: Implement unit tests
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubepug/kubepug/pkg/results"
+	"github.com/kubepug/kubepug/pkg/store/generatedstore"
+)
+
+func TestGetFieldDeprecations(t *testing.T) {
+	tests := []struct {
+		name       string
+		minVersion string
+		deprecated []results.ResultItem
+		deleted    []results.ResultItem
+	}{
+		{
+			name:       "fields deprecated and deleted on the version should be reported",
+			minVersion: "v1.25",
+			deprecated: []results.ResultItem{
+				{
+					Group: "apps", Version: "v1", Kind: "Deployment", K8sVersion: "1.19",
+					Field:       "spec.template.metadata.annotations[seccomp.security.alpha.kubernetes.io/pod]",
+					Description: "Deprecated: the seccompProfile field of the securityContext should be used instead.",
+					Items:       []results.Item{{ObjectName: "web", Namespace: "apps", Location: "../../../../test/testdata/fields/manifests/deployment.yaml", Scope: "OBJECT", Document: 1, Line: 1, Column: 1, File: true}},
+				},
+				{
+					Group: "apps", Version: "v1", Kind: "Deployment", K8sVersion: "unknown",
+					Field:       "spec.template.spec.serviceAccount",
+					Description: "DeprecatedServiceAccount is a deprecated alias for ServiceAccountName.\nDeprecated: Use serviceAccountName instead.",
+					Items:       []results.Item{{ObjectName: "web", Namespace: "apps", Location: "../../../../test/testdata/fields/manifests/deployment.yaml", Scope: "OBJECT", Document: 1, Line: 1, Column: 1, File: true}},
+				},
+				{
+					Version: "v1", Kind: "Pod", K8sVersion: "unknown",
+					Field:       "spec.serviceAccount",
+					Description: "DeprecatedServiceAccount is a deprecated alias for ServiceAccountName.\nDeprecated: Use serviceAccountName instead.",
+					Items:       []results.Item{{ObjectName: "debug", Namespace: "default", Location: "../../../../test/testdata/fields/manifests/pod.yaml", Scope: "OBJECT", Document: 1, Line: 4, Column: 3, File: true}},
+				},
+			},
+			deleted: []results.ResultItem{
+				{
+					Group: "apps", Version: "v1", Kind: "Deployment", K8sVersion: "1.25",
+					Field:       "spec.template.spec.containers.*.securityContext.legacyProfile",
+					Description: "Deprecated: removed field used by the tests.",
+					Items:       []results.Item{{ObjectName: "web", Namespace: "apps", Location: "../../../../test/testdata/fields/manifests/deployment.yaml", Scope: "OBJECT", Document: 1, Line: 1, Column: 1, File: true}},
+				},
+				{
+					Version: "v1", Kind: "Pod", K8sVersion: "1.25",
+					Field:       "metadata.clusterName",
+					Description: "Deprecated: ClusterName is a legacy field that was always cleared by the system and never used.",
+					Items:       []results.Item{{ObjectName: "debug", Namespace: "default", Location: "../../../../test/testdata/fields/manifests/pod.yaml", Scope: "OBJECT", Document: 1, Line: 4, Column: 3, File: true}},
+				},
+			},
+		},
+		{
+			name:       "fields deprecated after the version should not be reported",
+			minVersion: "v1.18",
+			deprecated: []results.ResultItem{
+				{
+					Group: "apps", Version: "v1", Kind: "Deployment", K8sVersion: "unknown",
+					Field:       "spec.template.spec.serviceAccount",
+					Description: "DeprecatedServiceAccount is a deprecated alias for ServiceAccountName.\nDeprecated: Use serviceAccountName instead.",
+					Items:       []results.Item{{ObjectName: "web", Namespace: "apps", Location: "../../../../test/testdata/fields/manifests/deployment.yaml", Scope: "OBJECT", Document: 1, Line: 1, Column: 1, File: true}},
+				},
+				{
+					Version: "v1", Kind: "Pod", K8sVersion: "unknown",
+					Field:       "spec.serviceAccount",
+					Description: "DeprecatedServiceAccount is a deprecated alias for ServiceAccountName.\nDeprecated: Use serviceAccountName instead.",
+					Items:       []results.Item{{ObjectName: "debug", Namespace: "default", Location: "../../../../test/testdata/fields/manifests/pod.yaml", Scope: "OBJECT", Document: 1, Line: 4, Column: 3, File: true}},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			storer, err := generatedstore.NewGeneratedStore(ctx, generatedstore.StoreConfig{
+				Path:       "../../../../test/testdata/fields/data.json",
+				MinVersion: tt.minVersion,
+			})
+			require.NoError(t, err)
+
+			input := NewFileInputFromItems(make(FileItems), storer)
+			input.Fields = true
+			require.NoError(t, input.AddLocation("../../../../test/testdata/fields/manifests", WalkConfig{}))
+
+			deprecated, deleted, err := input.GetDeprecations(ctx)
+			require.NoError(t, err)
+			require.ElementsMatch(t, tt.deprecated, deprecated)
+			require.ElementsMatch(t, tt.deleted, deleted)
+		})
+	}
+}
+
+func TestGetDeprecationsWithoutFields(t *testing.T) {
+	ctx := context.Background()
+	storer, err := generatedstore.NewGeneratedStore(ctx, generatedstore.StoreConfig{
+		Path:       "../../../../test/testdata/fields/data.json",
+		MinVersion: "v1.25",
+	})
+	require.NoError(t, err)
+
+	input, err := NewFileInput("../../../../test/testdata/fields/manifests", storer)
+	require.NoError(t, err)
+	require.Nil(t, input.bodies)
+
+	deprecated, deleted, err := input.GetDeprecations(ctx)
+	require.NoError(t, err)
+	require.Empty(t, deprecated)
+	require.Empty(t, deleted)
+}
+
+func TestObjectBodies(t *testing.T) {
+	manifests := []byte("apiVersion: v1\nkind: Pod\nmetadata:\n  name: debug\nspec:\n  serviceAccount: legacy\n")
+
+	t.Run("bodies should not be kept when fields are disabled", func(t *testing.T) {
+		input := NewFileInputFromItems(make(FileItems), nil)
+		input.AddManifests(manifests, "pod.yaml")
+		require.Len(t, input.FileItems["v1/Pod"], 1)
+		require.Nil(t, input.bodies)
+	})
+
+	t.Run("bodies should be kept on the position of their items", func(t *testing.T) {
+		input := NewFileInputFromItems(FileItems{"v1/Pod": {{ObjectName: "without-body"}}}, nil)
+		input.Fields = true
+		input.AddManifests(manifests, "pod.yaml")
+		require.True(t, input.AddObject("v1", "Pod", results.Item{ObjectName: "object"}, map[string]interface{}{"kind": "Pod"}))
+
+		require.Len(t, input.FileItems["v1/Pod"], 3)
+		require.Nil(t, input.bodies.body("v1/Pod", 0))
+		require.Equal(t, map[string]interface{}{"serviceAccount": "legacy"}, input.bodies.body("v1/Pod", 1)["spec"])
+		require.Equal(t, map[string]interface{}{"kind": "Pod"}, input.bodies.body("v1/Pod", 2))
+		require.Nil(t, input.bodies.body("v1/Pod", 3))
+	})
+}
@@ -0,0 +1,112 @@
+package fileinput
+
+import (
+	"bytes"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// documentSeparator starts the lines separating the documents of a manifests file
+var documentSeparator = []byte("---")
+
+// document is one of the documents of a manifests file, starting at offset
+type document struct {
+	body   []byte
+	offset int
+}
+
+// splitDocuments splits the manifests on the separator lines. Any content following the separator
+// on the same line belongs to the next document, while the "---" found elsewhere, like the ones of
+// PEM blocks inside block scalars, are part of the documents
+func splitDocuments(manifests []byte) []document {
+	docs := []document{{}}
+	var offset int
+	for _, line := range bytes.SplitAfter(manifests, []byte("\n")) {
+		if isSeparator(line) {
+			docs = append(docs, document{body: append([]byte{}, line[len(documentSeparator):]...), offset: offset + len(documentSeparator)})
+		} else {
+			docs[len(docs)-1].body = append(docs[len(docs)-1].body, line...)
+		}
+		offset += len(line)
+	}
+	return docs
+}
+
+// isSeparator tells if a line separates two documents, being "---" followed by a space or the end of the line
+func isSeparator(line []byte) bool {
+	if !bytes.HasPrefix(line, documentSeparator) {
+		return false
+	}
+	return len(line) == len(documentSeparator) || strings.ContainsRune(" \t\r\n", rune(line[len(documentSeparator)]))
+}
+
+// position is the line and column, starting at 1, where an object was found. The zero value
+// means the position is unknown
+type position struct {
+	line   int
+	column int
+}
+
+// from converts a position relative to the document starting at offset into a position
+// relative to the whole manifests
+func (p position) from(manifests []byte, offset int) position {
+	if p.line == 0 {
+		return p
+	}
+
+	before := manifests[:offset]
+	line := bytes.Count(before, []byte("\n")) + 1
+	column := offset - bytes.LastIndexByte(before, '\n')
+
+	if p.line == 1 {
+		p.column += column - 1
+	}
+	p.line += line - 1
+	return p
+}
+
+// apiVersionPositions returns the position of the apiVersion key of the object on a document and,
+// when it is a List, of each of its items. Invalid documents have no positions
+func apiVersionPositions(document []byte) (object position, items []position) {
+	var node yaml.Node
+	if err := yaml.Unmarshal(document, &node); err != nil || len(node.Content) == 0 {
+		return object, nil
+	}
+
+	root := node.Content[0]
+	object = keyPosition(root, "apiVersion")
+	if list := mappingValue(root, "items"); list != nil && list.Kind == yaml.SequenceNode {
+		items = make([]position, 0, len(list.Content))
+		for _, item := range list.Content {
+			items = append(items, keyPosition(item, "apiVersion"))
+		}
+	}
+	return object, items
+}
+
+// keyPosition returns the position of a key of a mapping node
+func keyPosition(node *yaml.Node, key string) position {
+	if node.Kind != yaml.MappingNode {
+		return position{}
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return position{line: node.Content[i].Line, column: node.Content[i].Column}
+		}
+	}
+	return position{}
+}
+
+// mappingValue returns the value of a key of a mapping node, or nil if not found
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
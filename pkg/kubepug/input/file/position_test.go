@@ -0,0 +1,108 @@
+package fileinput
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const positionManifests = `# a comment before the first document
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: first
+---
+---
+kind: Secret
+apiVersion: v1
+metadata:
+  name: second
+--- {"kind": "Service", "apiVersion": "v1", "metadata": {"name": "third"}}
+---
+apiVersion: v1
+kind: List
+items:
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: fourth
+-   kind: ConfigMap
+    apiVersion: v1
+    metadata:
+      name: fifth
+`
+
+const certificateManifests = `apiVersion: v1
+kind: Secret
+metadata:
+  name: tls
+stringData:
+  tls.crt: |
+    -----BEGIN CERTIFICATE-----
+    MIIBszCCAVmgAwIBAgIUXs
+    -----END CERTIFICATE-----
+---  
+apiVersion: extensions/v1beta1
+kind: DaemonSet
+metadata:
+  name: agent
+`
+
+func TestFileItemsPositions(t *testing.T) {
+	location := filepath.Join(t.TempDir(), "manifests.yaml")
+	require.NoError(t, os.WriteFile(location, []byte(positionManifests), 0o600))
+
+	items, err := GetFileItems(location)
+	require.NoError(t, err)
+
+	type pos struct{ document, line, column int }
+	positions := make(map[string]pos)
+	for _, objects := range items {
+		for _, item := range objects {
+			require.Equal(t, location, item.Location)
+			require.True(t, item.File)
+			positions[item.ObjectName] = pos{item.Document, item.Line, item.Column}
+		}
+	}
+	require.Equal(t, map[string]pos{
+		"first":  {1, 2, 1},
+		"second": {2, 9, 1},
+		"third":  {3, 12, 25},
+		"fourth": {4, 17, 3},
+		"fifth":  {4, 22, 5},
+	}, positions)
+
+	t.Run("dashes inside the documents should not separate them", func(t *testing.T) {
+		location := filepath.Join(t.TempDir(), "certificate.yaml")
+		require.NoError(t, os.WriteFile(location, []byte(certificateManifests), 0o600))
+
+		items, err := GetFileItems(location)
+		require.NoError(t, err)
+		require.Len(t, items, 2)
+
+		secret := items["v1/Secret"]
+		require.Len(t, secret, 1)
+		require.Equal(t, "tls", secret[0].ObjectName)
+		require.Equal(t, 1, secret[0].Document)
+
+		daemonset := items["extensions/v1beta1/DaemonSet"]
+		require.Len(t, daemonset, 1)
+		require.Equal(t, "agent", daemonset[0].ObjectName)
+		require.Equal(t, []int{2, 11, 1}, []int{daemonset[0].Document, daemonset[0].Line, daemonset[0].Column})
+	})
+
+	t.Run("manifests not read from a file should not have positions", func(t *testing.T) {
+		fileItems := make(FileItems)
+		fileItems.AddManifests([]byte(positionManifests), "helm:default/app@1")
+		for _, objects := range fileItems {
+			for _, item := range objects {
+				require.False(t, item.File)
+				require.Zero(t, item.Document)
+				require.Zero(t, item.Line)
+				require.Zero(t, item.Column)
+			}
+		}
+	})
+}
@@ -29,55 +29,73 @@ type FileStruct struct {
 // the input files
 type FileItems map[string][]results.Item
 
-// GetFileItems converts a bunch of input files into a map of Items
+// objectBodies contains the body of the objects of FileItems, on the same key and position of their
+// items. Items added without a body have a nil one
+type objectBodies map[string][]map[string]interface{}
+
+// body returns the body of the item on a position of a FileItems key, or nil if it is not known
+func (bodies objectBodies) body(key string, position int) map[string]interface{} {
+	if position >= len(bodies[key]) {
+		return nil
+	}
+	return bodies[key][position]
+}
+
+// GetFileItems converts a bunch of input files into a map of Items. If location is a directory,
+// only the files directly inside it are parsed
 func GetFileItems(location string) (fileItems FileItems, err error) {
+	return GetFileItemsWithConfig(location, WalkConfig{})
+}
+
+// GetFileItemsWithConfig converts a bunch of input files into a map of Items, traversing
+// directories as defined on the WalkConfig
+func GetFileItemsWithConfig(location string, config WalkConfig) (fileItems FileItems, err error) {
 	fileItems = make(FileItems)
+	if err := fileItems.addLocation(location, config, nil); err != nil {
+		return nil, err
+	}
+	return fileItems, nil
+}
+
+// addLocation inserts the items of the files on location into the FileItems Map. The body of
+// the objects is inserted into bodies, unless it is nil
+func (fileItems FileItems) addLocation(location string, config WalkConfig, bodies objectBodies) error {
 	// First we get the list of files
 
 	if location == "-" {
-		fileItems.yamlToMap(nil, "-", false)
-		return fileItems, nil
+		fileItems.yamlToMap("-", bodies)
+		return nil
 	}
 
-	var filesInfo []os.FileInfo
-
 	fileLocation, err := os.Stat(location)
 	if os.IsNotExist(err) {
-		return nil, fmt.Errorf("input location %s does not exist", location)
+		return fmt.Errorf("input location %s does not exist", location)
+	}
+	if err != nil {
+		return fmt.Errorf("error to read input location %s: %w", location, err)
 	}
 
+	files := []string{location}
 	if fileLocation.IsDir() {
-		entries, err := os.ReadDir(location) // Too lazy to refactor right now :P
+		files, err = listFiles(location, config)
 		if err != nil {
-			return nil, fmt.Errorf("error to read input location %s: %w", location, err)
+			return err
 		}
-		for _, entry := range entries {
-			info, err := entry.Info()
-			if err != nil {
-				return nil, fmt.Errorf("error converting filedir to fileinfo: %w", err)
-			}
-			filesInfo = append(filesInfo, info)
-		}
-	} else {
-		filesInfo = append(filesInfo, fileLocation)
 	}
 
 	// Then we loop each of them and feed the fileItems struct
-	for _, file := range filesInfo {
-		fileItems.yamlToMap(file, location, fileLocation.IsDir())
+	for _, file := range files {
+		fileItems.yamlToMap(file, bodies)
 	}
 
-	return fileItems, nil
+	return nil
 }
 
 // Yaml to Map takes a YAML and insert its items into the FileItems Map
-func (fileItems FileItems) yamlToMap(file os.FileInfo, location string, isDir bool) {
-	if isDir {
-		location = fmt.Sprintf("%s/%s", location, file.Name())
-	}
-
+func (fileItems FileItems) yamlToMap(location string, bodies objectBodies) {
 	var err error
 	var yamlFiles []byte
+	item := results.Item{Location: location, File: true}
 	if location == "-" {
 		reader := bufio.NewReader(os.Stdin)
 		yamlFiles, err = io.ReadAll(reader)
@@ -85,7 +103,8 @@ func (fileItems FileItems) yamlToMap(file os.FileInfo, location string, isDir bo
 			log.Warningf("Unable to read from STDIN: %s", err)
 			return
 		}
-		location = "STDIN" // Changing here just to be beautified in the list
+		// Changing here just to be beautified in the list
+		item = results.Item{Location: "STDIN"}
 	} else {
 		yamlFiles, err = os.ReadFile(location)
 		if err != nil {
@@ -94,54 +113,116 @@ func (fileItems FileItems) yamlToMap(file os.FileInfo, location string, isDir bo
 		}
 	}
 
-	yamlObjects := bytes.Split(yamlFiles, []byte("---"))
+	fileItems.addManifests(yamlFiles, item, true, bodies)
+}
+
+// AddManifests parses a set of YAML or JSON manifests, separated by "---", and inserts its
+// items into the FileItems Map. location is used to identify where the items were found
+func (fileItems FileItems) AddManifests(manifests []byte, location string) {
+	fileItems.addManifests(manifests, results.Item{Location: location}, false, nil)
+}
+
+// addManifests parses the manifests as AddManifests. source is copied to every item, defining where the
+// manifests were read from. When positions is true, the manifests are the whole content read from there,
+// and the document, line and column of each object are recorded. The body of the objects is inserted
+// into bodies, unless it is nil
+func (fileItems FileItems) addManifests(manifests []byte, source results.Item, positions bool, bodies objectBodies) {
+	var document int
+	for _, doc := range splitDocuments(manifests) {
+		yamlObject, start := doc.body, doc.offset
+
+		var objectPos position
+		var itemsPos []position
+		if len(bytes.TrimSpace(yamlObject)) > 0 {
+			document++
+			if positions {
+				objectPos, itemsPos = apiVersionPositions(yamlObject)
+				objectPos = objectPos.from(manifests, start)
+				for i := range itemsPos {
+					itemsPos[i] = itemsPos[i].from(manifests, start)
+				}
+			}
+		}
 
-	for _, yamlObject := range yamlObjects {
 		var obj FileStruct
 		err := yaml.Unmarshal(yamlObject, &obj)
 		if err != nil {
 			log.Warningf("Found invalid yaml: %v. Skipping to next", err)
 			continue
 		}
+		// The body is only read when it is kept, so the deprecated fields can be looked up later
+		var body map[string]interface{}
+		if bodies != nil {
+			if err := yaml.Unmarshal(yamlObject, &body); err != nil {
+				log.Debugf("unable to read the body of the object: %v", err)
+			}
+		}
 		if len(obj.Items) > 0 {
-			for item := range obj.Items {
-				fileItems.addObject(&obj.Items[item], location)
+			itemBodies, _ := body["items"].([]interface{})
+			for i := range obj.Items {
+				item := source
+				if i < len(itemsPos) {
+					item.Document, item.Line, item.Column = document, itemsPos[i].line, itemsPos[i].column
+				}
+				var itemBody map[string]interface{}
+				if i < len(itemBodies) {
+					itemBody, _ = itemBodies[i].(map[string]interface{})
+				}
+				fileItems.addObject(&obj.Items[i], item, itemBody, bodies)
 			}
 		} else {
-			fileItems.addObject(&obj, location)
+			item := source
+			if positions {
+				item.Document, item.Line, item.Column = document, objectPos.line, objectPos.column
+			}
+			fileItems.addObject(&obj, item, body, bodies)
 		}
 	}
 }
 
-func (fileItems FileItems) addObject(obj *FileStruct, location string) {
+// addObject inserts the object into the FileItems Map. item contains where the object was found
+func (fileItems FileItems) addObject(obj *FileStruct, item results.Item, body map[string]interface{}, bodies objectBodies) {
+	item.ObjectName = obj.Metadata.Name
+	item.Namespace = obj.Metadata.Namespace
+	item.Scope = "OBJECT"
+
+	if !fileItems.addItem(obj.APIVersion, obj.Kind, item, body, bodies) {
+		log.Infof("YAML file does not contain apiVersion or Kind: %s  Skipping to next", item.Location)
+	}
+}
+
+// AddItem inserts an item into the FileItems Map, indexed by its apiVersion and kind. It returns
+// false if the item cannot be indexed because apiVersion or kind are empty
+func (fileItems FileItems) AddItem(apiVersion, kind string, item results.Item) bool {
+	return fileItems.addItem(apiVersion, kind, item, nil, nil)
+}
+
+// addItem inserts an item as AddItem, inserting its body into bodies at the same key and
+// position, unless bodies is nil
+func (fileItems FileItems) addItem(apiVersion, kind string, item results.Item, body map[string]interface{}, bodies objectBodies) bool {
 	var group, version, objIndex string
 
-	gv := strings.Split(obj.APIVersion, "/")
+	gv := strings.Split(apiVersion, "/")
 	if len(gv) > 1 {
 		group = gv[0]
 		version = gv[1]
-		objIndex = fmt.Sprintf("%s/%s/%s", group, version, obj.Kind)
+		objIndex = fmt.Sprintf("%s/%s/%s", group, version, kind)
 	} else {
 		version = gv[0]
-		objIndex = fmt.Sprintf("%s/%s", version, obj.Kind)
+		objIndex = fmt.Sprintf("%s/%s", version, kind)
 	}
 
-	if version == "" || obj.Kind == "" {
-		log.Infof("YAML file does not contain apiVersion or Kind: %s  Skipping to next", location)
-		return
+	if version == "" || kind == "" {
+		return false
 	}
 
-	item := results.Item{
-		ObjectName: obj.Metadata.Name,
-		Namespace:  obj.Metadata.Namespace,
-		Location:   location,
-		Scope:      "OBJECT",
-	}
-
-	if items, ok := fileItems[objIndex]; !ok {
-		fileItems[objIndex] = []results.Item{item}
-	} else {
-		items = append(items, item)
-		fileItems[objIndex] = items
+	fileItems[objIndex] = append(fileItems[objIndex], item)
+	if bodies != nil {
+		// Items added without a body keep the positions aligned
+		for len(bodies[objIndex]) < len(fileItems[objIndex])-1 {
+			bodies[objIndex] = append(bodies[objIndex], nil)
+		}
+		bodies[objIndex] = append(bodies[objIndex], body)
 	}
+	return true
 }
@@ -1,3 +1,30 @@
 package fileinput
 
-// TODO: Implement unit tests
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const manifestsDir = "../../../../test/testdata/manifests"
+
+func TestGetFileItemsWithConfig(t *testing.T) {
+	t.Run("non existing location should fail", func(t *testing.T) {
+		_, err := GetFileItemsWithConfig("/tmp123/lalala", WalkConfig{})
+		require.ErrorContains(t, err, "does not exist")
+	})
+
+	t.Run("directory should be parsed", func(t *testing.T) {
+		items, err := GetFileItemsWithConfig(manifestsDir, WalkConfig{Recursive: true, Include: []string{"*.yaml"}})
+		require.NoError(t, err)
+		require.Len(t, items["extensions/v1beta1/Ingress"], 1)
+		require.Len(t, items["policy/v1beta1/PodSecurityPolicy"], 4)
+	})
+
+	t.Run("files not included should be skipped", func(t *testing.T) {
+		items, err := GetFileItemsWithConfig(manifestsDir, WalkConfig{Include: []string{"psp*.yaml"}})
+		require.NoError(t, err)
+		require.NotContains(t, items, "extensions/v1beta1/Ingress")
+		require.Len(t, items["policy/v1beta1/PodSecurityPolicy"], 4)
+	})
+}
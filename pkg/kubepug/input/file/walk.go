@@ -0,0 +1,180 @@
+package fileinput
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// WalkConfig defines how a directory used as input should be traversed
+type WalkConfig struct {
+	// Recursive defines if the subdirectories of the input location should also be traversed.
+	// When false, only the files directly inside the input directory are parsed
+	Recursive bool
+	// Include contains glob patterns (eg.: **/*.yaml) that a file should match to be parsed.
+	// Patterns without a "/" are matched against the file name only. If empty, all the files are parsed
+	Include []string
+	// Exclude contains glob patterns (eg.: .git, node_modules, **/charts/**) of files and directories
+	// that should be skipped. Patterns without a "/" are matched against the file or directory name only
+	Exclude []string
+	// FollowSymlinks defines if symbolic links pointing to directories should be traversed.
+	// Symbolic links pointing to files are always parsed
+	FollowSymlinks bool
+}
+
+type walker struct {
+	config WalkConfig
+	// visited contains the real path of the directories already traversed, so symlink loops
+	// don't make us walk forever
+	visited map[string]struct{}
+	files   []string
+}
+
+// listFiles returns the files inside a directory that should be parsed, following
+// the rules defined on WalkConfig
+func listFiles(root string, config WalkConfig) ([]string, error) {
+	w := &walker{
+		config:  config,
+		visited: make(map[string]struct{}),
+	}
+
+	realRoot, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		return nil, fmt.Errorf("error to read input location %s: %w", root, err)
+	}
+	w.visited[realRoot] = struct{}{}
+
+	if err := w.walk(root, ""); err != nil {
+		return nil, err
+	}
+	return w.files, nil
+}
+
+func (w *walker) walk(dir, rel string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("error to read input location %s: %w", dir, err)
+	}
+
+	for _, entry := range entries {
+		location := filepath.Join(dir, entry.Name())
+		relPath := path.Join(rel, entry.Name())
+
+		if w.excluded(relPath) {
+			log.Debugf("%s is excluded, skipping", location)
+			continue
+		}
+
+		isDir := entry.IsDir()
+		if entry.Type()&os.ModeSymlink != 0 {
+			target, err := os.Stat(location)
+			if err != nil {
+				log.Warningf("Unable to follow symlink %s, skipping: %s", location, err)
+				continue
+			}
+			isDir = target.IsDir()
+			if isDir && !w.config.FollowSymlinks {
+				log.Infof("%s is a symlink to a directory, skipping", location)
+				continue
+			}
+		}
+
+		if !isDir {
+			if w.included(relPath) {
+				w.files = append(w.files, location)
+			}
+			continue
+		}
+
+		if !w.config.Recursive {
+			log.Debugf("%s is a directory and recursive mode is disabled, skipping", location)
+			continue
+		}
+
+		realPath, err := filepath.EvalSymlinks(location)
+		if err != nil {
+			log.Warningf("Unable to read directory %s, skipping: %s", location, err)
+			continue
+		}
+		if _, ok := w.visited[realPath]; ok {
+			log.Infof("%s was already visited, skipping", location)
+			continue
+		}
+		w.visited[realPath] = struct{}{}
+
+		if err := w.walk(location, relPath); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *walker) excluded(relPath string) bool {
+	for _, pattern := range w.config.Exclude {
+		if matchPattern(pattern, relPath) {
+			return true
+		}
+	}
+	return false
+}
+
+func (w *walker) included(relPath string) bool {
+	if len(w.config.Include) == 0 {
+		return true
+	}
+	for _, pattern := range w.config.Include {
+		if matchPattern(pattern, relPath) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchPattern verifies if a slash separated relative path matches a pattern. Patterns
+// without a "/" are compared against the last element of the path only
+func matchPattern(pattern, relPath string) bool {
+	pattern = strings.TrimPrefix(filepath.ToSlash(pattern), "./")
+	if !strings.Contains(pattern, "/") {
+		matched, err := path.Match(pattern, path.Base(relPath))
+		return err == nil && matched
+	}
+	return matchGlob(strings.Split(pattern, "/"), strings.Split(relPath, "/"))
+}
+
+// matchGlob matches path segments against pattern segments, where a "**" segment
+// matches zero or more path segments
+func matchGlob(pattern, segments []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			// Collapse consecutive "**" and try to match the rest of the pattern
+			// on every possible position
+			for len(pattern) > 0 && pattern[0] == "**" {
+				pattern = pattern[1:]
+			}
+			if len(pattern) == 0 {
+				return true
+			}
+			for i := range segments {
+				if matchGlob(pattern, segments[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+
+		if len(segments) == 0 {
+			return false
+		}
+		matched, err := path.Match(pattern[0], segments[0])
+		if err != nil || !matched {
+			return false
+		}
+		pattern = pattern[1:]
+		segments = segments[1:]
+	}
+	return len(segments) == 0
+}
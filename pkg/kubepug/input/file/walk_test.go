@@ -0,0 +1,150 @@
+package fileinput
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_matchPattern(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		path    string
+		want    bool
+	}{
+		{
+			name:    "name pattern matches any level",
+			pattern: "*.yaml",
+			path:    "apps/team/prod/deploy.yaml",
+			want:    true,
+		},
+		{
+			name:    "name pattern does not match other extension",
+			pattern: "*.yaml",
+			path:    "apps/team/prod/README.md",
+			want:    false,
+		},
+		{
+			name:    "directory name matches",
+			pattern: "node_modules",
+			path:    "apps/node_modules",
+			want:    true,
+		},
+		{
+			name:    "double star matches zero directories",
+			pattern: "**/*.yaml",
+			path:    "deploy.yaml",
+			want:    true,
+		},
+		{
+			name:    "double star matches many directories",
+			pattern: "**/*.yaml",
+			path:    "apps/team/prod/deploy.yaml",
+			want:    true,
+		},
+		{
+			name:    "double star in the middle",
+			pattern: "apps/**/prod/*.yaml",
+			path:    "apps/team/prod/deploy.yaml",
+			want:    true,
+		},
+		{
+			name:    "double star in the middle with wrong directory",
+			pattern: "apps/**/prod/*.yaml",
+			path:    "apps/team/dev/deploy.yaml",
+			want:    false,
+		},
+		{
+			name:    "relative pattern prefix is ignored",
+			pattern: "./apps/*.yaml",
+			path:    "apps/deploy.yaml",
+			want:    true,
+		},
+		{
+			name:    "trailing double star matches everything below",
+			pattern: "**/charts/**",
+			path:    "apps/charts/templates",
+			want:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, matchPattern(tt.pattern, tt.path))
+		})
+	}
+}
+
+func createTree(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	files := []string{
+		"root.yaml",
+		"README.md",
+		"apps/team1/prod/deploy.yaml",
+		"apps/team1/dev/deploy.yml",
+		".git/config",
+		"node_modules/pkg/manifest.yaml",
+	}
+	for _, f := range files {
+		location := filepath.Join(root, f)
+		require.NoError(t, os.MkdirAll(filepath.Dir(location), 0o755))
+		require.NoError(t, os.WriteFile(location, []byte("{}"), 0o600))
+	}
+	return root
+}
+
+func relFiles(t *testing.T, root string, files []string) []string {
+	t.Helper()
+	rel := make([]string, 0, len(files))
+	for _, f := range files {
+		r, err := filepath.Rel(root, f)
+		require.NoError(t, err)
+		rel = append(rel, filepath.ToSlash(r))
+	}
+	return rel
+}
+
+func Test_listFiles(t *testing.T) {
+	root := createTree(t)
+
+	t.Run("non recursive should return only first level files", func(t *testing.T) {
+		files, err := listFiles(root, WalkConfig{})
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{"root.yaml", "README.md"}, relFiles(t, root, files))
+	})
+
+	t.Run("recursive with include and exclude", func(t *testing.T) {
+		files, err := listFiles(root, WalkConfig{
+			Recursive: true,
+			Include:   []string{"**/*.yaml", "*.yml"},
+			Exclude:   []string{".git", "node_modules"},
+		})
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{
+			"root.yaml",
+			"apps/team1/prod/deploy.yaml",
+			"apps/team1/dev/deploy.yml",
+		}, relFiles(t, root, files))
+	})
+
+	t.Run("symlinks to directories are followed only when enabled", func(t *testing.T) {
+		linkRoot := t.TempDir()
+		require.NoError(t, os.Symlink(filepath.Join(root, "apps"), filepath.Join(linkRoot, "linked")))
+		// A loop should not make the walker run forever
+		require.NoError(t, os.Symlink(linkRoot, filepath.Join(linkRoot, "loop")))
+
+		files, err := listFiles(linkRoot, WalkConfig{Recursive: true})
+		require.NoError(t, err)
+		require.Empty(t, files)
+
+		files, err = listFiles(linkRoot, WalkConfig{Recursive: true, FollowSymlinks: true})
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{
+			"linked/team1/prod/deploy.yaml",
+			"linked/team1/dev/deploy.yml",
+		}, relFiles(t, linkRoot, files))
+	})
+}
@@ -0,0 +1,5 @@
+// Package helminput contains the methods used to get Deprecated objects
+// from a Helm Chart, rendering its templates
+package helminput
+
+// import "github.com/kubepug/kubepug/pkg/kubepug/input/helm"
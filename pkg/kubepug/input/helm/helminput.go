@@ -0,0 +1,177 @@
+package helminput
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/cli/values"
+	"helm.sh/helm/v3/pkg/engine"
+	"helm.sh/helm/v3/pkg/getter"
+
+	fileinput "github.com/kubepug/kubepug/pkg/kubepug/input/file"
+	"github.com/kubepug/kubepug/pkg/store"
+)
+
+const (
+	defaultReleaseName = "release-name"
+	defaultNamespace   = "default"
+)
+
+// Config defines the chart that should be rendered and the values used to render it
+type Config struct {
+	// Chart is the location of a chart directory or of a packaged (.tgz) chart
+	Chart string
+	// ValueFiles is a list of values files, the same as helm -f/--values flag
+	ValueFiles []string
+	// Values is a list of overrides on the key=value format, the same as helm --set flag
+	Values []string
+	// ReleaseName is the name of the release exposed to the templates. Defaults to release-name
+	ReleaseName string
+	// Namespace is the namespace of the release exposed to the templates. Defaults to default
+	Namespace string
+	// KubeVersion is the Kubernetes version exposed to the templates as .Capabilities.KubeVersion.
+	// If empty, Helm default version is used
+	KubeVersion string
+	// Fields enables the verification of deprecated and deleted fields on the rendered objects
+	Fields bool
+}
+
+// HelmInput defines a struct that will be used when comparing APIs against a rendered Helm Chart
+type HelmInput struct {
+	*fileinput.FileInput
+}
+
+// NewHelmInput renders the chart and returns the struct HelmInput populated with the rendered objects
+func NewHelmInput(config Config, storer store.DefinitionStorer) (*HelmInput, error) {
+	input := fileinput.NewFileInputFromItems(make(fileinput.FileItems), storer)
+	input.Fields = config.Fields
+	if err := addChartManifests(config, input.AddManifests); err != nil {
+		return nil, err
+	}
+
+	return &HelmInput{FileInput: input}, nil
+}
+
+// GetChartItems renders the chart templates and converts the rendered manifests into a map of Items.
+// The location of each item is the template it was rendered from, relative to the chart
+func GetChartItems(config Config) (fileinput.FileItems, error) {
+	fileItems := make(fileinput.FileItems)
+	if err := addChartManifests(config, fileItems.AddManifests); err != nil {
+		return nil, err
+	}
+	return fileItems, nil
+}
+
+// addChartManifests renders the chart templates, adding the manifests of each one sorted by their location
+func addChartManifests(config Config, add func(manifests []byte, location string)) error {
+	manifests, err := renderChart(config)
+	if err != nil {
+		return err
+	}
+
+	locations := make([]string, 0, len(manifests))
+	for location := range manifests {
+		locations = append(locations, location)
+	}
+	sort.Strings(locations)
+
+	for _, location := range locations {
+		add([]byte(manifests[location]), location)
+	}
+	return nil
+}
+
+// renderChart returns the rendered manifests of a chart, including the CRDs, indexed by
+// their location inside the chart
+func renderChart(config Config) (map[string]string, error) {
+	if config.Chart == "" {
+		return nil, fmt.Errorf("chart location cannot be empty")
+	}
+
+	chrt, err := loader.Load(config.Chart)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load chart %s: %w", config.Chart, err)
+	}
+
+	if chrt.Metadata != nil && chrt.Metadata.Type == "library" {
+		return nil, fmt.Errorf("chart %s is a library chart and cannot be rendered", config.Chart)
+	}
+
+	valueOpts := &values.Options{
+		ValueFiles: config.ValueFiles,
+		Values:     config.Values,
+	}
+	// No getters are passed, so values files can only be read from the local filesystem
+	vals, err := valueOpts.MergeValues(getter.Providers{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse chart values: %w", err)
+	}
+
+	if err := chartutil.ProcessDependenciesWithMerge(chrt, vals); err != nil {
+		return nil, fmt.Errorf("failed to process chart dependencies: %w", err)
+	}
+
+	caps := chartutil.DefaultCapabilities.Copy()
+	if config.KubeVersion != "" {
+		kubeVersion, err := chartutil.ParseKubeVersion(config.KubeVersion)
+		if err != nil {
+			return nil, fmt.Errorf("invalid kubernetes version %s: %w", config.KubeVersion, err)
+		}
+		caps.KubeVersion = *kubeVersion
+	}
+
+	options := chartutil.ReleaseOptions{
+		Name:      config.ReleaseName,
+		Namespace: config.Namespace,
+		Revision:  1,
+		IsInstall: true,
+	}
+	if options.Name == "" {
+		options.Name = defaultReleaseName
+	}
+	if options.Namespace == "" {
+		options.Namespace = defaultNamespace
+	}
+
+	renderValues, err := chartutil.ToRenderValues(chrt, vals, options, caps)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate chart values: %w", err)
+	}
+
+	rendered, err := engine.Render(chrt, renderValues)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render chart %s: %w", config.Chart, err)
+	}
+
+	manifests := make(map[string]string)
+	for name, content := range rendered {
+		base := path.Base(name)
+		// Partials and NOTES are not Kubernetes objects
+		if strings.HasPrefix(base, "_") || base == "NOTES.txt" || strings.TrimSpace(content) == "" {
+			continue
+		}
+		manifests[chartLocation(name)] = content
+	}
+
+	for _, crd := range chrt.CRDObjects() {
+		manifests[chartLocation(crd.Filename)] = string(crd.File.Data)
+	}
+
+	log.Debugf("rendered %d manifests from chart %s", len(manifests), chrt.Name())
+	return manifests, nil
+}
+
+// chartLocation removes the chart name from the rendered file name, so
+// mychart/templates/deployment.yaml becomes templates/deployment.yaml
+func chartLocation(name string) string {
+	name = path.Clean(strings.ReplaceAll(name, "\\", "/"))
+	if _, location, found := strings.Cut(name, "/"); found {
+		return location
+	}
+	return name
+}
@@ -0,0 +1,73 @@
+package helminput
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubepug/kubepug/pkg/store/generatedstore"
+	"github.com/kubepug/kubepug/pkg/store/mock"
+)
+
+const (
+	sampleChart  = "../../../../test/testdata/charts/sample"
+	modernValues = "../../../../test/testdata/charts/values-modern.yaml"
+)
+
+func TestGetChartItems(t *testing.T) {
+	t.Run("empty chart should fail", func(t *testing.T) {
+		_, err := GetChartItems(Config{})
+		require.ErrorContains(t, err, "chart location cannot be empty")
+	})
+
+	t.Run("non existing chart should fail", func(t *testing.T) {
+		_, err := GetChartItems(Config{Chart: "/tmp123/lalala"})
+		require.ErrorContains(t, err, "failed to load chart")
+	})
+
+	t.Run("rendered items should be attributed to the templates", func(t *testing.T) {
+		items, err := GetChartItems(Config{Chart: sampleChart, Namespace: "myns", ReleaseName: "pug"})
+		require.NoError(t, err)
+		require.Len(t, items, 3)
+
+		require.Len(t, items["extensions/v1beta1/DaemonSet"], 1)
+		require.Equal(t, "templates/daemonset.yaml", items["extensions/v1beta1/DaemonSet"][0].Location)
+		require.Equal(t, "pug-sample", items["extensions/v1beta1/DaemonSet"][0].ObjectName)
+		require.Equal(t, "myns", items["extensions/v1beta1/DaemonSet"][0].Namespace)
+
+		require.Equal(t, "templates/configmap.yaml", items["v1/ConfigMap"][0].Location)
+		require.Equal(t, "crds/crd.yaml", items["apiextensions.k8s.io/v1/CustomResourceDefinition"][0].Location)
+	})
+
+	t.Run("values files and overrides should be used", func(t *testing.T) {
+		items, err := GetChartItems(Config{Chart: sampleChart, ValueFiles: []string{modernValues}})
+		require.NoError(t, err)
+		require.Contains(t, items, "apps/v1/DaemonSet")
+		require.NotContains(t, items, "extensions/v1beta1/DaemonSet")
+
+		items, err = GetChartItems(Config{Chart: sampleChart, ValueFiles: []string{modernValues}, Values: []string{"legacyDaemonSet=true"}})
+		require.NoError(t, err)
+		require.Contains(t, items, "extensions/v1beta1/DaemonSet")
+	})
+
+	t.Run("invalid kubernetes version should fail", func(t *testing.T) {
+		_, err := GetChartItems(Config{Chart: sampleChart, KubeVersion: "lalala"})
+		require.ErrorContains(t, err, "invalid kubernetes version")
+	})
+}
+
+func TestGetDeprecations(t *testing.T) {
+	storer, err := generatedstore.NewGeneratedStoreFromBytes([]byte(mock.MockValidData), generatedstore.StoreConfig{})
+	require.NoError(t, err)
+
+	input, err := NewHelmInput(Config{Chart: sampleChart}, storer)
+	require.NoError(t, err)
+
+	deprecated, deleted, err := input.GetDeprecations(context.Background())
+	require.NoError(t, err)
+	require.Empty(t, deprecated)
+	require.Len(t, deleted, 1)
+	require.Equal(t, "DaemonSet", deleted[0].Kind)
+	require.Equal(t, "templates/daemonset.yaml", deleted[0].Items[0].Location)
+}
@@ -0,0 +1,103 @@
+package k8sinput
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	fileinput "github.com/kubepug/kubepug/pkg/kubepug/input/file"
+	"github.com/kubepug/kubepug/pkg/results"
+	"github.com/kubepug/kubepug/pkg/store"
+)
+
+// hasDeprecatedFields verifies if an API has fields that are deprecated or deleted on the store.
+// It is always false when Fields is disabled or the store doesn't know about fields
+func (f *K8sInput) hasDeprecatedFields(ctx context.Context, gv schema.GroupVersion, kind string) (bool, error) {
+	if !f.Fields {
+		return false, nil
+	}
+	lister, ok := f.Store.(store.FieldLister)
+	if !ok {
+		return false, nil
+	}
+
+	fields, err := lister.GetDeprecatedFields(ctx, gv.Group, gv.Version, kind)
+	if err != nil {
+		return false, err
+	}
+	return len(fields) > 0, nil
+}
+
+// newFieldsInput returns the input where the objects are added, so their fields can be compared with the store
+func (f *K8sInput) newFieldsInput() *fileinput.FileInput {
+	fieldsInput := fileinput.NewFileInputFromItems(make(fileinput.FileItems), f.Store)
+	fieldsInput.IgnoreExactGroup = f.IgnoreExactGroup
+	fieldsInput.IncludePrefixGroup = f.IncludePrefixGroup
+	fieldsInput.Fields = true
+	return fieldsInput
+}
+
+// serverFilledField is a deprecated field that the API Server fills with the value of the field
+// replacing it, its alias, when converting the objects
+type serverFilledField struct {
+	path  []string
+	alias string
+}
+
+// serverFilledFields are found on every object read from a cluster that sets their alias, even when
+// nobody wrote them, so they are not reported. They are the serviceAccount of the Pods and of the
+// Pod templates of PodTemplates, workloads and CronJobs
+var serverFilledFields = []serverFilledField{
+	{path: []string{"spec", "serviceAccount"}, alias: "serviceAccountName"},
+	{path: []string{"spec", "template", "spec", "serviceAccount"}, alias: "serviceAccountName"},
+	{path: []string{"template", "spec", "serviceAccount"}, alias: "serviceAccountName"},
+	{path: []string{"spec", "jobTemplate", "spec", "template", "spec", "serviceAccount"}, alias: "serviceAccountName"},
+}
+
+// addFieldItems records the body of the objects, so their fields can be compared with the store.
+// Only the objects listed with their whole body are recorded
+func addFieldItems(fieldsInput *fileinput.FileInput, gv schema.GroupVersion, kind string, objects []metav1.Object) {
+	for i := range objects {
+		obj, ok := objects[i].(*unstructured.Unstructured)
+		if !ok {
+			continue
+		}
+		fieldsInput.AddObject(gv.String(), kind, results.ObjectItem(obj.GetName(), obj.GetNamespace()), withoutServerFilledFields(obj.Object))
+	}
+}
+
+// withoutServerFilledFields returns the body without the server filled fields holding the value of
+// their alias. Only the maps on the path of the removed fields are copied, so the listed object is
+// not changed
+func withoutServerFilledFields(body map[string]interface{}) map[string]interface{} {
+	for _, field := range serverFilledFields {
+		parentPath := field.path[:len(field.path)-1]
+		value, found, err := unstructured.NestedString(body, field.path...)
+		if err != nil || !found {
+			continue
+		}
+		alias, _, _ := unstructured.NestedString(body, append(append([]string{}, parentPath...), field.alias)...)
+		if value == alias {
+			body = withoutField(body, field.path)
+		}
+	}
+	return body
+}
+
+// withoutField returns a copy of the body without the field, copying only the maps on its path
+func withoutField(body map[string]interface{}, path []string) map[string]interface{} {
+	copied := make(map[string]interface{}, len(body))
+	for key, value := range body {
+		copied[key] = value
+	}
+	if len(path) == 1 {
+		delete(copied, path[0])
+		return copied
+	}
+	if child, ok := body[path[0]].(map[string]interface{}); ok {
+		copied[path[0]] = withoutField(child, path[1:])
+	}
+	return copied
+}
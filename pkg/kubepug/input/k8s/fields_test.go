@@ -0,0 +1,107 @@
+package k8sinput
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/kubepug/kubepug/pkg/store/generatedstore"
+)
+
+func newFieldsStore(t *testing.T) *generatedstore.GeneratedStore {
+	storer, err := generatedstore.NewGeneratedStore(context.Background(), generatedstore.StoreConfig{
+		Path:       "../../../../test/testdata/fields/data.json",
+		MinVersion: "v1.25",
+	})
+	require.NoError(t, err)
+	return storer
+}
+
+func newPod(name string, spec map[string]interface{}) *unstructured.Unstructured {
+	obj := newListObject("v1", "Pod", name, "default")
+	obj.Object["spec"] = spec
+	return obj
+}
+
+func TestHasDeprecatedFields(t *testing.T) {
+	input := &K8sInput{Store: newFieldsStore(t), Fields: true}
+
+	hasFields, err := input.hasDeprecatedFields(context.Background(), schema.GroupVersion{Version: "v1"}, "Pod")
+	require.NoError(t, err)
+	require.True(t, hasFields)
+
+	hasFields, err = input.hasDeprecatedFields(context.Background(), schema.GroupVersion{Version: "v1"}, "Namespace")
+	require.NoError(t, err)
+	require.False(t, hasFields)
+
+	input.Fields = false
+	hasFields, err = input.hasDeprecatedFields(context.Background(), schema.GroupVersion{Version: "v1"}, "Pod")
+	require.NoError(t, err)
+	require.False(t, hasFields)
+}
+
+func TestGetFieldDeprecations(t *testing.T) {
+	// Pods read from a cluster always have the serviceAccount filled by the API Server
+	legacy := newPod("legacy", map[string]interface{}{"serviceAccount": "legacy", "serviceAccountName": "legacy"})
+	legacy.Object["metadata"].(map[string]interface{})["clusterName"] = "legacy"
+	objects := []metav1.Object{
+		legacy,
+		newPod("current", map[string]interface{}{"serviceAccount": "current", "serviceAccountName": "current"}),
+		// Objects listed just with their metadata are ignored
+		&metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{Name: "metadata", Namespace: "default"}},
+	}
+
+	input := &K8sInput{Store: newFieldsStore(t)}
+	fieldsInput := input.newFieldsInput()
+	addFieldItems(fieldsInput, schema.GroupVersion{Version: "v1"}, "Pod", objects)
+	require.Len(t, fieldsInput.FileItems["v1/Pod"], 2)
+
+	deprecated, deleted, err := fieldsInput.GetFieldDeprecations(context.Background())
+	require.NoError(t, err)
+	require.Empty(t, deprecated)
+	require.Len(t, deleted, 1)
+	require.Equal(t, "metadata.clusterName", deleted[0].Field)
+	require.Equal(t, "Pod", deleted[0].Kind)
+	require.Len(t, deleted[0].Items, 1)
+	require.Equal(t, "legacy", deleted[0].Items[0].ObjectName)
+
+	// The listed objects should not be changed
+	require.Equal(t, "legacy", legacy.Object["spec"].(map[string]interface{})["serviceAccount"])
+}
+
+func TestWithoutServerFilledFields(t *testing.T) {
+	tests := []struct {
+		name string
+		body map[string]interface{}
+		want map[string]interface{}
+	}{
+		{
+			name: "fields holding the value of their alias should be removed",
+			body: map[string]interface{}{"spec": map[string]interface{}{
+				"template": map[string]interface{}{"spec": map[string]interface{}{"serviceAccount": "web", "serviceAccountName": "web"}},
+			}},
+			want: map[string]interface{}{"spec": map[string]interface{}{
+				"template": map[string]interface{}{"spec": map[string]interface{}{"serviceAccountName": "web"}},
+			}},
+		},
+		{
+			name: "fields with a different value than their alias should be kept",
+			body: map[string]interface{}{"spec": map[string]interface{}{"serviceAccount": "legacy", "serviceAccountName": "web"}},
+			want: map[string]interface{}{"spec": map[string]interface{}{"serviceAccount": "legacy", "serviceAccountName": "web"}},
+		},
+		{
+			name: "objects without the fields should be kept",
+			body: map[string]interface{}{"spec": map[string]interface{}{"replicas": int64(1)}},
+			want: map[string]interface{}{"spec": map[string]interface{}{"replicas": int64(1)}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, withoutServerFilledFields(tt.body))
+		})
+	}
+}
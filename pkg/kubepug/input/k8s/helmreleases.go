@@ -0,0 +1,126 @@
+package k8sinput
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+
+	json "github.com/goccy/go-json"
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	fileinput "github.com/kubepug/kubepug/pkg/kubepug/input/file"
+	"github.com/kubepug/kubepug/pkg/results"
+)
+
+const (
+	// helmReleaseSelector and helmReleaseType are the label and the type Helm 3 uses on the
+	// Secrets storing the releases
+	helmReleaseSelector = "owner=helm"
+	helmReleaseType     = "helm.sh/release.v1"
+)
+
+var secretsgvr = schema.GroupVersionResource{
+	Version:  "v1",
+	Resource: "secrets",
+}
+
+var gzipMagic = []byte{0x1f, 0x8b, 0x08}
+
+// helmRelease contains the fields of a Helm release that are relevant to find deprecated APIs
+type helmRelease struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Version   int    `json:"version"`
+	Manifest  string `json:"manifest"`
+	Hooks     []struct {
+		Manifest string `json:"manifest"`
+	} `json:"hooks"`
+}
+
+// getHelmReleaseDeprecations decodes the manifests stored by Helm on the release Secrets, and compares
+// the objects of each release revision with Kubepug store.
+// Even if the live objects were already converted by the API Server, a release manifest containing a removed
+// API breaks the next helm upgrade
+func (f *K8sInput) getHelmReleaseDeprecations(ctx context.Context) (deprecated, deleted []results.ResultItem, err error) {
+	secrets, err := f.listObjects(ctx, secretsgvr, true, metav1.ListOptions{
+		LabelSelector: helmReleaseSelector,
+		FieldSelector: "type=" + helmReleaseType,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list helm release secrets: %w", err)
+	}
+
+	fileItems := make(fileinput.FileItems)
+	for i := range secrets {
+		release, err := decodeHelmReleaseSecret(&secrets[i])
+		if err != nil {
+			logrus.Warningf("failed to decode helm release secret %s/%s, skipping: %s", secrets[i].GetNamespace(), secrets[i].GetName(), err)
+			continue
+		}
+
+		location := fmt.Sprintf("helm:%s/%s@%d", release.Namespace, release.Name, release.Version)
+		fileItems.AddManifests([]byte(release.Manifest), location)
+		for _, hook := range release.Hooks {
+			fileItems.AddManifests([]byte(hook.Manifest), location)
+		}
+	}
+
+	releaseInput := fileinput.NewFileInputFromItems(fileItems, f.Store)
+	releaseInput.IgnoreExactGroup = f.IgnoreExactGroup
+	releaseInput.IncludePrefixGroup = f.IncludePrefixGroup
+
+	return releaseInput.GetDeprecations(ctx)
+}
+
+// decodeHelmReleaseSecret decodes a release stored by Helm on a Secret. The release is a
+// gzipped JSON, encoded as base64, inside the "release" key of the Secret data
+func decodeHelmReleaseSecret(secret *unstructured.Unstructured) (*helmRelease, error) {
+	if t, _, _ := unstructured.NestedString(secret.Object, "type"); t != helmReleaseType {
+		return nil, fmt.Errorf("secret is not of type %s", helmReleaseType)
+	}
+
+	data, found, err := unstructured.NestedString(secret.Object, "data", "release")
+	if err != nil || !found {
+		return nil, fmt.Errorf("secret does not contain a release")
+	}
+
+	// The first decode reverts the Secret data encoding, the second one reverts the Helm encoding
+	encoded, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode secret data: %w", err)
+	}
+	decoded, err := base64.StdEncoding.DecodeString(string(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode release: %w", err)
+	}
+
+	if bytes.HasPrefix(decoded, gzipMagic) {
+		reader, err := gzip.NewReader(bytes.NewReader(decoded))
+		if err != nil {
+			return nil, fmt.Errorf("failed to uncompress release: %w", err)
+		}
+		defer reader.Close()
+		decoded, err = io.ReadAll(reader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to uncompress release: %w", err)
+		}
+	}
+
+	release := &helmRelease{}
+	if err := json.Unmarshal(decoded, release); err != nil {
+		return nil, fmt.Errorf("failed to parse release: %w", err)
+	}
+
+	// Older releases may not contain the namespace, so we rely on where the Secret is
+	if release.Namespace == "" {
+		release.Namespace = secret.GetNamespace()
+	}
+
+	return release, nil
+}
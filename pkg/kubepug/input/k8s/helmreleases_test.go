@@ -0,0 +1,130 @@
+package k8sinput
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"testing"
+
+	json "github.com/goccy/go-json"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	"github.com/kubepug/kubepug/pkg/store/generatedstore"
+	"github.com/kubepug/kubepug/pkg/store/mock"
+)
+
+const releaseManifest = `---
+# Source: app/templates/daemonset.yaml
+apiVersion: extensions/v1beta1
+kind: DaemonSet
+metadata:
+  name: app
+  namespace: apps
+---
+# Source: app/templates/configmap.yaml
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app
+`
+
+func encodeRelease(t *testing.T, release *helmRelease, compress bool) string {
+	t.Helper()
+	data, err := json.Marshal(release)
+	require.NoError(t, err)
+
+	if compress {
+		var buf bytes.Buffer
+		w := gzip.NewWriter(&buf)
+		_, err = w.Write(data)
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+		data = buf.Bytes()
+	}
+
+	helmEncoded := base64.StdEncoding.EncodeToString(data)
+	return base64.StdEncoding.EncodeToString([]byte(helmEncoded))
+}
+
+func newReleaseSecret(name, namespace, secretType, data string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Secret",
+			"metadata": map[string]interface{}{
+				"name":      name,
+				"namespace": namespace,
+				"labels": map[string]interface{}{
+					"owner": "helm",
+				},
+			},
+			"type": secretType,
+			"data": map[string]interface{}{
+				"release": data,
+			},
+		},
+	}
+}
+
+func Test_decodeHelmReleaseSecret(t *testing.T) {
+	release := &helmRelease{Name: "app", Version: 3, Manifest: releaseManifest}
+
+	t.Run("compressed release should be decoded", func(t *testing.T) {
+		got, err := decodeHelmReleaseSecret(newReleaseSecret("sh.helm.release.v1.app.v3", "apps", helmReleaseType, encodeRelease(t, release, true)))
+		require.NoError(t, err)
+		require.Equal(t, "app", got.Name)
+		require.Equal(t, "apps", got.Namespace)
+		require.Equal(t, 3, got.Version)
+		require.Equal(t, releaseManifest, got.Manifest)
+	})
+
+	t.Run("uncompressed release should be decoded", func(t *testing.T) {
+		got, err := decodeHelmReleaseSecret(newReleaseSecret("sh.helm.release.v1.app.v3", "apps", helmReleaseType, encodeRelease(t, release, false)))
+		require.NoError(t, err)
+		require.Equal(t, releaseManifest, got.Manifest)
+	})
+
+	t.Run("other secret types should fail", func(t *testing.T) {
+		_, err := decodeHelmReleaseSecret(newReleaseSecret("sh.helm.release.v1.app.v3", "apps", "Opaque", encodeRelease(t, release, true)))
+		require.ErrorContains(t, err, "secret is not of type")
+	})
+
+	t.Run("invalid data should fail", func(t *testing.T) {
+		_, err := decodeHelmReleaseSecret(newReleaseSecret("sh.helm.release.v1.app.v3", "apps", helmReleaseType, "xpto!!"))
+		require.ErrorContains(t, err, "failed to decode secret data")
+	})
+}
+
+func TestGetHelmReleaseDeprecations(t *testing.T) {
+	storer, err := generatedstore.NewGeneratedStoreFromBytes([]byte(mock.MockValidData), generatedstore.StoreConfig{})
+	require.NoError(t, err)
+
+	objects := []runtime.Object{
+		newReleaseSecret("sh.helm.release.v1.app.v1", "apps", helmReleaseType, encodeRelease(t, &helmRelease{Name: "app", Namespace: "apps", Version: 1, Manifest: releaseManifest}, true)),
+		newReleaseSecret("sh.helm.release.v1.app.v2", "apps", helmReleaseType, encodeRelease(t, &helmRelease{Name: "app", Namespace: "apps", Version: 2, Manifest: releaseManifest}, true)),
+		newReleaseSecret("broken", "apps", helmReleaseType, "xpto!!"),
+	}
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		secretsgvr: "SecretList",
+	}, objects...)
+
+	input := &K8sInput{
+		Store:  storer,
+		Client: client,
+	}
+
+	deprecated, deleted, err := input.getHelmReleaseDeprecations(context.Background())
+	require.NoError(t, err)
+	require.Empty(t, deprecated)
+	require.Len(t, deleted, 1)
+	require.Equal(t, "DaemonSet", deleted[0].Kind)
+	require.Len(t, deleted[0].Items, 2)
+
+	locations := []string{deleted[0].Items[0].Location, deleted[0].Items[1].Location}
+	require.ElementsMatch(t, []string{"helm:apps/app@1", "helm:apps/app@2"}, locations)
+}
@@ -3,13 +3,16 @@ package k8sinput
 import (
 	"context"
 	"fmt"
+	"sync"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/metadata"
 
 	"github.com/kubepug/kubepug/pkg/errors"
+	fileinput "github.com/kubepug/kubepug/pkg/kubepug/input/file"
 	"github.com/kubepug/kubepug/pkg/results"
 	"github.com/kubepug/kubepug/pkg/store"
 	"github.com/kubepug/kubepug/pkg/utils"
@@ -32,6 +35,37 @@ type K8sInput struct {
 	IncludePrefixGroup []string
 	// If an API is inside the IgnoreGroup it will be bypassed
 	IgnoreExactGroup []string
+
+	// HelmReleases enables the analysis of the manifests stored by Helm on the release Secrets
+	HelmReleases bool
+
+	// ManagedFields enables the analysis of the API versions used by the managers that wrote the objects.
+	// As all the objects must be listed, this is more expensive than just listing the deprecated APIs
+	ManagedFields bool
+
+	// Fields enables the analysis of the deprecated and deleted fields used by the objects. The whole
+	// body of the objects of the APIs with deprecated fields must be listed
+	Fields bool
+
+	// Rules enables the analysis of the webhook configurations, RBAC roles and APIServices that
+	// reference deprecated or deleted APIs
+	Rules bool
+
+	// Selector restricts the namespaces and the objects that are listed
+	Selector Selector
+
+	// MetadataClient is used to list just the metadata of the objects. If not set, Client is used
+	MetadataClient metadata.Interface
+
+	// Concurrency defines how many resources are listed at the same time. Defaults to 4
+	Concurrency int
+
+	// PageSize defines how many objects are requested on each list call. Defaults to 500
+	PageSize int64
+
+	// namespaces caches the namespaces listed one by one when listing all the namespaces is forbidden
+	namespaces     []string
+	namespacesLock sync.Mutex
 }
 
 var deprecatedAPIReplacements = map[string]schema.GroupVersionResource{
@@ -48,8 +82,8 @@ var apisvcgvr = schema.GroupVersionResource{
 	Resource: "apiservices",
 }
 
-func (f *K8sInput) IgnoreAPIService() error {
-	apisvcList, err := f.Client.Resource(apisvcgvr).List(context.TODO(), metav1.ListOptions{})
+func (f *K8sInput) IgnoreAPIService(ctx context.Context) error {
+	apisvcList, err := f.Client.Resource(apisvcgvr).List(ctx, metav1.ListOptions{})
 	if err != nil && !apierrors.IsNotFound(err) {
 		return fmt.Errorf("failed to get apiservices: %w", err)
 	}
@@ -75,8 +109,8 @@ func (f *K8sInput) IgnoreAPIService() error {
 
 // GetDeprecated retrieves the map of FileItems and compares with Kubepug store,
 // returning the set of Deprecated results
-func (f *K8sInput) GetDeprecations() (deprecated, deleted []results.ResultItem, err error) {
-	if err := f.IgnoreAPIService(); err != nil {
+func (f *K8sInput) GetDeprecations(ctx context.Context) (deprecated, deleted []results.ResultItem, err error) {
+	if err := f.IgnoreAPIService(ctx); err != nil {
 		return deprecated, deleted, err
 	}
 
@@ -88,37 +122,117 @@ func (f *K8sInput) GetDeprecations() (deprecated, deleted []results.ResultItem,
 		logrus.Warningf("failed to discovery some apiresources, they will be skipped: %s", err)
 	}
 
+	tasks := make([]*resourceTask, 0)
 	for _, reslist := range apiresources {
-		deprecatedRes, deletedRes, err := f.getResourceDeprecation(reslist)
+		resourceTasks, err := f.getResourceTasks(ctx, reslist)
+		if err != nil {
+			return deprecated, deleted, err
+		}
+		tasks = append(tasks, resourceTasks...)
+	}
+
+	if err := f.listTasks(ctx, tasks); err != nil {
+		return deprecated, deleted, err
+	}
+
+	managedItems := make(fileinput.FileItems)
+	fieldsInput := f.newFieldsInput()
+	for _, task := range tasks {
+		if f.ManagedFields {
+			addManagedFields(managedItems, task.gv, task.resource.Kind, task.objects)
+		}
+
+		if task.fields {
+			addFieldItems(fieldsInput, task.gv, task.resource.Kind, task.objects)
+		}
+
+		if !task.deprecated || len(task.objects) == 0 {
+			continue
+		}
+
+		items := make([]results.Item, 0, len(task.objects))
+		for _, obj := range task.objects {
+			items = append(items, results.ObjectItem(obj.GetName(), obj.GetNamespace()))
+		}
+
+		result := results.CreateItem(task.gv.Group, task.gv.Version, task.resource.Kind, items)
+		result.Description = task.apiResult.Description
+		if task.apiResult.Replacement != nil {
+			result.Replacement = task.apiResult.Replacement
+			result.ReplacementAvailable = task.apiResult.ReplacementAvailable
+		}
+
+		result.K8sVersion = task.apiResult.DeprecationVersion
+		if task.apiResult.DeletedVersion != "" {
+			result.K8sVersion = task.apiResult.DeletedVersion
+			deleted = append(deleted, result)
+			continue
+		}
+		deprecated = append(deprecated, result)
+	}
+
+	if f.ManagedFields {
+		managedDeprecated, managedDeleted, err := f.getManagedFieldsDeprecations(ctx, managedItems)
 		if err != nil {
 			return deprecated, deleted, err
 		}
-		deprecated = append(deprecated, deprecatedRes...)
-		deleted = append(deleted, deletedRes...)
+		deprecated = results.MergeResultItems(deprecated, managedDeprecated)
+		deleted = results.MergeResultItems(deleted, managedDeleted)
+	}
+
+	if f.Fields {
+		fieldsDeprecated, fieldsDeleted, err := fieldsInput.GetFieldDeprecations(ctx)
+		if err != nil {
+			return deprecated, deleted, err
+		}
+		deprecated = results.MergeResultItems(deprecated, fieldsDeprecated)
+		deleted = results.MergeResultItems(deleted, fieldsDeleted)
+	}
+
+	if f.Rules {
+		rulesDeprecated, rulesDeleted, err := f.getRulesDeprecations(ctx)
+		if err != nil {
+			return deprecated, deleted, err
+		}
+		deprecated = results.MergeResultItems(deprecated, rulesDeprecated)
+		deleted = results.MergeResultItems(deleted, rulesDeleted)
+	}
+
+	if f.HelmReleases {
+		helmDeprecated, helmDeleted, err := f.getHelmReleaseDeprecations(ctx)
+		if err != nil {
+			return deprecated, deleted, err
+		}
+		deprecated = results.MergeResultItems(deprecated, helmDeprecated)
+		deleted = results.MergeResultItems(deleted, helmDeleted)
 	}
 
 	return deprecated, deleted, nil
 }
 
-func (f *K8sInput) getResourceDeprecation(resources *metav1.APIResourceList) (deprecated, deleted []results.ResultItem, err error) {
+// getResourceTasks returns the resources of a group version whose objects must be listed, being
+// the deprecated ones, the ones with deprecated fields when Fields is enabled or, when ManagedFields
+// is enabled, all of them
+func (f *K8sInput) getResourceTasks(ctx context.Context, resources *metav1.APIResourceList) ([]*resourceTask, error) {
 	if resources == nil {
-		return nil, nil, nil
+		return nil, nil
 	}
 	gv, err := schema.ParseGroupVersion(resources.GroupVersion)
 	if err != nil {
 		logrus.Warningf("couldn't parse group %s, skipping", resources.GroupVersion)
-		return nil, nil, nil
+		return nil, nil
 	}
 
 	if !utils.ShouldParse(gv.Group, f.IgnoreExactGroup, f.IncludePrefixGroup) {
 		logrus.Info("Ignoring group", gv.Group)
-		return nil, nil, nil
+		return nil, nil
 	}
 
+	tasks := make([]*resourceTask, 0)
 	for i := range resources.APIResources {
 		replacement, err := f.checkForReplacement(gv.Group, gv.Version, resources.APIResources[i].Kind)
 		if err != nil {
-			return nil, nil, err
+			return nil, err
 		}
 
 		// If there is a proper API replacement, we can just skip
@@ -127,57 +241,43 @@ func (f *K8sInput) getResourceDeprecation(resources *metav1.APIResourceList) (de
 			continue
 		}
 
-		apiResult, err := f.Store.GetAPIDefinition(context.Background(), gv.Group, gv.Version, resources.APIResources[i].Kind)
+		apiResult, err := f.Store.GetAPIDefinition(ctx, gv.Group, gv.Version, resources.APIResources[i].Kind)
 		if err != nil {
 			if !errors.IsErrAPINotFound(err) {
-				return deprecated, deleted, err
+				return nil, err
 			}
 		}
 
-		if apiResult.DeprecationVersion == "" && apiResult.DeletedVersion == "" {
-			continue
-		}
-
-		items, err := getResources(f.Client, gv.Group, gv.Version, resources.APIResources[i].Name)
+		listable := isListable(&resources.APIResources[i])
+		hasFields, err := f.hasDeprecatedFields(ctx, gv, resources.APIResources[i].Kind)
 		if err != nil {
-			return deprecated, deleted, err
+			return nil, err
 		}
+		hasFields = hasFields && listable
 
-		if len(items) == 0 {
+		isDeprecated := apiResult.DeprecationVersion != "" || apiResult.DeletedVersion != ""
+		if !isDeprecated && !hasFields && (!f.ManagedFields || !listable) {
 			continue
 		}
 
-		result := results.CreateItem(gv.Group, gv.Version, resources.APIResources[i].Kind, items)
-		result.Description = apiResult.Description
-		if apiResult.Replacement != nil {
-			result.Replacement = apiResult.Replacement
-		}
-
-		result.K8sVersion = apiResult.DeprecationVersion
-		if apiResult.DeletedVersion != "" {
-			result.K8sVersion = apiResult.DeletedVersion
-			deleted = append(deleted, result)
+		// Cluster scoped objects don't belong to any namespace, so they are not listed when only
+		// some namespaces are included
+		if len(f.Selector.IncludeNamespaces) > 0 && !resources.APIResources[i].Namespaced {
+			if isDeprecated {
+				logrus.Warningf("skipping the cluster scoped resource %s of %s, as only the objects of the included namespaces are analysed", resources.APIResources[i].Name, gv.String())
+			}
 			continue
 		}
-		deprecated = append(deprecated, result)
-	}
-	return deprecated, deleted, nil
-}
-
-func getResources(dynClient dynamic.Interface, group, version, resource string) ([]results.Item, error) {
-	gvr := schema.GroupVersionResource{Group: group, Version: version, Resource: resource}
-	list, err := dynClient.Resource(gvr).List(context.TODO(), metav1.ListOptions{})
-	if apierrors.IsNotFound(err) || apierrors.IsMethodNotSupported(err) {
-		return make([]results.Item, 0), nil
-	}
 
-	if err != nil {
-		return nil, fmt.Errorf("failed to List objects of type %s/%s/%s. \nError: %v", group, version, resource, err)
+		tasks = append(tasks, &resourceTask{
+			gv:         gv,
+			resource:   resources.APIResources[i],
+			apiResult:  apiResult,
+			deprecated: isDeprecated,
+			fields:     hasFields,
+		})
 	}
-
-	items := results.ListObjects(list.Items)
-
-	return items, nil
+	return tasks, nil
 }
 
 // Before checking for the API, we need to verify if it already have a proper replacement on the server.
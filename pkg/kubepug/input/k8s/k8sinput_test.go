@@ -1,3 +1,65 @@
 package k8sinput
 
-// TODO: Implement unit tests
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubepug/kubepug/pkg/store/generatedstore"
+	"github.com/kubepug/kubepug/pkg/store/mock"
+)
+
+// clusterScopedData adds to the mock data a deprecated cluster scoped API
+const clusterScopedData = `[
+	{"group": "extensions", "version": "v1beta1", "kind": "PodSecurityPolicy", "deprecated_version": {"version_major": 1, "version_minor": 11}},
+`
+
+func TestGetResourceTasks(t *testing.T) {
+	storer, err := generatedstore.NewGeneratedStoreFromBytes([]byte(clusterScopedData+strings.TrimPrefix(strings.TrimSpace(mock.MockValidData), "[")), generatedstore.StoreConfig{})
+	require.NoError(t, err)
+
+	resources := &metav1.APIResourceList{
+		GroupVersion: "extensions/v1beta1",
+		APIResources: []metav1.APIResource{
+			{Name: "daemonsets", Kind: "DaemonSet", Namespaced: true, Verbs: metav1.Verbs{"list"}},
+			{Name: "podsecuritypolicies", Kind: "PodSecurityPolicy", Verbs: metav1.Verbs{"list"}},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		selector Selector
+		want     []string
+	}{
+		{
+			name: "deprecated resources should be listed",
+			want: []string{"daemonsets", "podsecuritypolicies"},
+		},
+		{
+			name:     "cluster scoped resources should be skipped when namespaces are included",
+			selector: Selector{IncludeNamespaces: []string{"team-a"}},
+			want:     []string{"daemonsets"},
+		},
+		{
+			name:     "cluster scoped resources should be listed when namespaces are excluded",
+			selector: Selector{ExcludeNamespaces: []string{"kube-system"}},
+			want:     []string{"daemonsets", "podsecuritypolicies"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := &K8sInput{Store: storer, Selector: tt.selector}
+			tasks, err := input.getResourceTasks(context.Background(), resources)
+			require.NoError(t, err)
+
+			names := make([]string, 0, len(tasks))
+			for _, task := range tasks {
+				names = append(names, task.resource.Name)
+			}
+			require.Equal(t, tt.want, names)
+		})
+	}
+}
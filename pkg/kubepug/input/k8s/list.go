@@ -0,0 +1,225 @@
+package k8sinput
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/pager"
+)
+
+var namespacesgvr = schema.GroupVersionResource{
+	Version:  "v1",
+	Resource: "namespaces",
+}
+
+// Selector restricts the objects listed from the cluster
+type Selector struct {
+	// IncludeNamespaces are the only namespaces whose objects are listed. When set,
+	// cluster scoped objects are not listed
+	IncludeNamespaces []string
+	// ExcludeNamespaces are the namespaces whose objects are not listed
+	ExcludeNamespaces []string
+	// LabelSelector and FieldSelector filter the listed objects, the same as kubectl --selector
+	// and --field-selector flags
+	LabelSelector string
+	FieldSelector string
+}
+
+// selectorOptions returns the ListOptions filtering the objects as defined on the Selector
+func (f *K8sInput) selectorOptions() metav1.ListOptions {
+	return metav1.ListOptions{
+		LabelSelector: f.Selector.LabelSelector,
+		FieldSelector: f.Selector.FieldSelector,
+	}
+}
+
+// pageFuncFor builds the function listing one page of objects of a resource on a namespace,
+// where an empty namespace means all the namespaces
+type pageFuncFor func(namespace string) pager.ListPageFunc
+
+// dynamicPages lists the whole objects, for the callers that need more than the metadata
+func (f *K8sInput) dynamicPages(gvr schema.GroupVersionResource) pageFuncFor {
+	return func(namespace string) pager.ListPageFunc {
+		return func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+			return f.Client.Resource(gvr).Namespace(namespace).List(ctx, opts)
+		}
+	}
+}
+
+// metadataPages lists just the objects metadata, reducing the memory used and the data transferred
+// when listing huge amounts of objects. It falls back to the dynamic client if there is no metadata client
+func (f *K8sInput) metadataPages(gvr schema.GroupVersionResource) pageFuncFor {
+	if f.MetadataClient == nil {
+		return f.dynamicPages(gvr)
+	}
+	return func(namespace string) pager.ListPageFunc {
+		return func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+			return f.MetadataClient.Resource(gvr).Namespace(namespace).List(ctx, opts)
+		}
+	}
+}
+
+// listMetadata lists the metadata of the objects of a resource, as defined by listObjects
+func (f *K8sInput) listMetadata(ctx context.Context, gvr schema.GroupVersionResource, namespaced bool, opts metav1.ListOptions) ([]metav1.Object, error) {
+	objects, err := f.list(ctx, f.metadataPages(gvr), gvr, namespaced, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]metav1.Object, 0, len(objects))
+	for _, obj := range objects {
+		accessor, err := meta.Accessor(obj)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the metadata of %s: %w", gvrString(gvr), err)
+		}
+		items = append(items, accessor)
+	}
+	return items, nil
+}
+
+// listObjects lists the objects of a resource in the namespaces defined by the Selector. When listing
+// a namespaced resource on all the namespaces is forbidden, it falls back to list each namespace
+// the user has access to
+func (f *K8sInput) listObjects(ctx context.Context, gvr schema.GroupVersionResource, namespaced bool, opts metav1.ListOptions) ([]unstructured.Unstructured, error) {
+	objects, err := f.list(ctx, f.dynamicPages(gvr), gvr, namespaced, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]unstructured.Unstructured, 0, len(objects))
+	for _, obj := range objects {
+		u, ok := obj.(*unstructured.Unstructured)
+		if !ok {
+			return nil, fmt.Errorf("unexpected object of type %T listing %s", obj, gvrString(gvr))
+		}
+		items = append(items, *u)
+	}
+	return items, nil
+}
+
+func (f *K8sInput) list(ctx context.Context, pages pageFuncFor, gvr schema.GroupVersionResource, namespaced bool, opts metav1.ListOptions) ([]runtime.Object, error) {
+	if len(f.Selector.IncludeNamespaces) > 0 {
+		if !namespaced {
+			return nil, nil
+		}
+		return f.listInNamespaces(ctx, pages, gvr, f.Selector.IncludeNamespaces, opts)
+	}
+
+	clusterOpts := opts
+	if namespaced {
+		clusterOpts.FieldSelector = excludeNamespacesSelector(opts.FieldSelector, f.Selector.ExcludeNamespaces)
+	}
+
+	objects, err := f.listPages(ctx, pages(metav1.NamespaceAll), clusterOpts)
+	if err == nil {
+		return objects, nil
+	}
+	if apierrors.IsNotFound(err) || apierrors.IsMethodNotSupported(err) {
+		return nil, nil
+	}
+	if !namespaced || !apierrors.IsForbidden(err) {
+		return nil, fmt.Errorf("failed to List objects of type %s. \nError: %w", gvrString(gvr), err)
+	}
+
+	logrus.Infof("listing %s on all the namespaces is forbidden, listing each namespace", gvrString(gvr))
+	namespaces, err := f.accessibleNamespaces(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return f.listInNamespaces(ctx, pages, gvr, namespaces, opts)
+}
+
+func (f *K8sInput) listInNamespaces(ctx context.Context, pages pageFuncFor, gvr schema.GroupVersionResource, namespaces []string, opts metav1.ListOptions) ([]runtime.Object, error) {
+	objects := make([]runtime.Object, 0)
+	for _, namespace := range namespaces {
+		namespaceObjects, err := f.listPages(ctx, pages(namespace), opts)
+		if err != nil {
+			if apierrors.IsForbidden(err) || apierrors.IsNotFound(err) || apierrors.IsMethodNotSupported(err) {
+				logrus.Debugf("unable to list %s on namespace %s, skipping: %s", gvrString(gvr), namespace, err)
+				continue
+			}
+			return nil, fmt.Errorf("failed to List objects of type %s on namespace %s. \nError: %w", gvrString(gvr), namespace, err)
+		}
+		objects = append(objects, namespaceObjects...)
+	}
+	return objects, nil
+}
+
+// listPages lists all the objects, requesting them in pages of PageSize objects
+func (f *K8sInput) listPages(ctx context.Context, pageFunc pager.ListPageFunc, opts metav1.ListOptions) ([]runtime.Object, error) {
+	p := pager.New(pageFunc)
+	if f.PageSize > 0 {
+		p.PageSize = f.PageSize
+	}
+
+	objects := make([]runtime.Object, 0)
+	err := p.EachListItem(ctx, opts, func(obj runtime.Object) error {
+		objects = append(objects, obj)
+		return nil
+	})
+	return objects, err
+}
+
+// accessibleNamespaces returns the namespaces that should be listed one by one. If listing the
+// namespaces is also forbidden, the namespace of the current kubeconfig context is used
+func (f *K8sInput) accessibleNamespaces(ctx context.Context) ([]string, error) {
+	f.namespacesLock.Lock()
+	defer f.namespacesLock.Unlock()
+
+	if f.namespaces != nil {
+		return f.namespaces, nil
+	}
+
+	namespaces := make([]string, 0)
+	list, err := f.Client.Resource(namespacesgvr).List(ctx, metav1.ListOptions{})
+	switch {
+	case err == nil:
+		for i := range list.Items {
+			namespaces = append(namespaces, list.Items[i].GetName())
+		}
+	case apierrors.IsForbidden(err):
+		if f.K8sconfig == nil {
+			return nil, fmt.Errorf("listing namespaces is forbidden and there is no kubeconfig namespace to fallback: %w", err)
+		}
+		namespace, _, nsErr := f.K8sconfig.ToRawKubeConfigLoader().Namespace()
+		if nsErr != nil {
+			return nil, fmt.Errorf("listing namespaces is forbidden and failed to get the kubeconfig namespace: %w", nsErr)
+		}
+		logrus.Warningf("listing namespaces is forbidden, only the namespace %s will be verified", namespace)
+		namespaces = append(namespaces, namespace)
+	default:
+		return nil, fmt.Errorf("failed to list namespaces: %w", err)
+	}
+
+	f.namespaces = make([]string, 0, len(namespaces))
+	for _, namespace := range namespaces {
+		if !contains(f.Selector.ExcludeNamespaces, namespace) {
+			f.namespaces = append(f.namespaces, namespace)
+		}
+	}
+	return f.namespaces, nil
+}
+
+// excludeNamespacesSelector adds to a field selector the namespaces that should not be listed
+func excludeNamespacesSelector(fieldSelector string, exclude []string) string {
+	selectors := make([]string, 0, len(exclude)+1)
+	if fieldSelector != "" {
+		selectors = append(selectors, fieldSelector)
+	}
+	for _, namespace := range exclude {
+		selectors = append(selectors, "metadata.namespace!="+namespace)
+	}
+	return strings.Join(selectors, ",")
+}
+
+func gvrString(gvr schema.GroupVersionResource) string {
+	return gvr.Group + "/" + gvr.Version + "/" + gvr.Resource
+}
@@ -0,0 +1,215 @@
+package k8sinput
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	metadatafake "k8s.io/client-go/metadata/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"k8s.io/utils/ptr"
+)
+
+var daemonsetsgvr = schema.GroupVersionResource{Group: "extensions", Version: "v1beta1", Resource: "daemonsets"}
+
+func newListObject(apiVersion, kind, name, namespace string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion(apiVersion)
+	obj.SetKind(kind)
+	obj.SetName(name)
+	obj.SetNamespace(namespace)
+	return obj
+}
+
+func newListClient(forbidden ...string) *dynamicfake.FakeDynamicClient {
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		daemonsetsgvr: "DaemonSetList",
+		namespacesgvr: "NamespaceList",
+	},
+		newListObject("extensions/v1beta1", "DaemonSet", "app", "apps"),
+		newListObject("extensions/v1beta1", "DaemonSet", "agent", "kube-system"),
+		newListObject("extensions/v1beta1", "DaemonSet", "other", "team-b"),
+		newListObject("v1", "Namespace", "apps", ""),
+		newListObject("v1", "Namespace", "kube-system", ""),
+		newListObject("v1", "Namespace", "team-b", ""),
+	)
+
+	// Listing on all the namespaces (or cluster scoped resources) is forbidden for the resources passed
+	for _, resource := range forbidden {
+		client.PrependReactor("list", resource, func(action clienttesting.Action) (bool, runtime.Object, error) {
+			if action.GetNamespace() != "" {
+				return false, nil, nil
+			}
+			return true, nil, apierrors.NewForbidden(schema.GroupResource{Resource: action.GetResource().Resource}, "", nil)
+		})
+	}
+	client.PrependReactor("list", "daemonsets", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		if action.GetNamespace() == "team-b" {
+			return true, nil, apierrors.NewForbidden(schema.GroupResource{Resource: "daemonsets"}, "", nil)
+		}
+		return false, nil, nil
+	})
+	return client
+}
+
+func objectNames(objects []unstructured.Unstructured) []string {
+	names := make([]string, 0, len(objects))
+	for i := range objects {
+		names = append(names, objects[i].GetNamespace()+"/"+objects[i].GetName())
+	}
+	return names
+}
+
+func TestListObjects(t *testing.T) {
+	t.Run("all the namespaces should be listed", func(t *testing.T) {
+		input := &K8sInput{Client: newListClient()}
+		objects, err := input.listObjects(context.Background(), daemonsetsgvr, true, metav1.ListOptions{})
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{"apps/app", "kube-system/agent", "team-b/other"}, objectNames(objects))
+	})
+
+	t.Run("only the included namespaces should be listed", func(t *testing.T) {
+		input := &K8sInput{Client: newListClient(), Selector: Selector{IncludeNamespaces: []string{"apps", "kube-system"}}}
+		objects, err := input.listObjects(context.Background(), daemonsetsgvr, true, metav1.ListOptions{})
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{"apps/app", "kube-system/agent"}, objectNames(objects))
+
+		objects, err = input.listObjects(context.Background(), namespacesgvr, false, metav1.ListOptions{})
+		require.NoError(t, err)
+		require.Empty(t, objects)
+	})
+
+	t.Run("forbidden list should fallback to each namespace", func(t *testing.T) {
+		input := &K8sInput{Client: newListClient("daemonsets"), Selector: Selector{ExcludeNamespaces: []string{"kube-system"}}}
+		objects, err := input.listObjects(context.Background(), daemonsetsgvr, true, metav1.ListOptions{})
+		require.NoError(t, err)
+		// team-b is forbidden, so it is skipped
+		require.ElementsMatch(t, []string{"apps/app"}, objectNames(objects))
+	})
+
+	t.Run("forbidden namespaces list should fallback to the kubeconfig namespace", func(t *testing.T) {
+		configFlags := genericclioptions.NewConfigFlags(false)
+		configFlags.Namespace = ptr.To("kube-system")
+		input := &K8sInput{Client: newListClient("daemonsets", "namespaces"), K8sconfig: configFlags}
+		objects, err := input.listObjects(context.Background(), daemonsetsgvr, true, metav1.ListOptions{})
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{"kube-system/agent"}, objectNames(objects))
+	})
+
+	t.Run("forbidden cluster scoped list should fail", func(t *testing.T) {
+		input := &K8sInput{Client: newListClient("namespaces")}
+		_, err := input.listObjects(context.Background(), namespacesgvr, false, metav1.ListOptions{})
+		require.ErrorContains(t, err, "failed to List objects of type /v1/namespaces")
+	})
+}
+
+func TestExcludeNamespacesSelector(t *testing.T) {
+	require.Equal(t, "", excludeNamespacesSelector("", nil))
+	require.Equal(t, "status.phase=Running", excludeNamespacesSelector("status.phase=Running", nil))
+	require.Equal(t, "status.phase=Running,metadata.namespace!=a,metadata.namespace!=b", excludeNamespacesSelector("status.phase=Running", []string{"a", "b"}))
+}
+
+func newMetadataObject(name, namespace string) *metav1.PartialObjectMetadata {
+	return &metav1.PartialObjectMetadata{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "extensions/v1beta1", Kind: "DaemonSet"},
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+	}
+}
+
+func TestListMetadata(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, metav1.AddMetaToScheme(scheme))
+	client := metadatafake.NewSimpleMetadataClient(scheme,
+		newMetadataObject("app", "apps"),
+		newMetadataObject("agent", "kube-system"),
+	)
+
+	input := &K8sInput{MetadataClient: client}
+	objects, err := input.listMetadata(context.Background(), daemonsetsgvr, true, metav1.ListOptions{})
+	require.NoError(t, err)
+
+	names := make([]string, 0, len(objects))
+	for _, obj := range objects {
+		names = append(names, obj.GetNamespace()+"/"+obj.GetName())
+	}
+	require.ElementsMatch(t, []string{"apps/app", "kube-system/agent"}, names)
+
+	t.Run("dynamic client should be used without a metadata client", func(t *testing.T) {
+		input := &K8sInput{Client: newListClient()}
+		objects, err := input.listMetadata(context.Background(), daemonsetsgvr, true, metav1.ListOptions{})
+		require.NoError(t, err)
+		require.Len(t, objects, 3)
+	})
+}
+
+func TestListPages(t *testing.T) {
+	pages := []*unstructured.UnstructuredList{
+		{Items: []unstructured.Unstructured{*newListObject("v1", "Secret", "a", "apps"), *newListObject("v1", "Secret", "b", "apps")}},
+		{Items: []unstructured.Unstructured{*newListObject("v1", "Secret", "c", "apps")}},
+	}
+	pages[0].SetContinue("page-2")
+
+	requests := make([]metav1.ListOptions, 0)
+	pageFunc := func(_ context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+		requests = append(requests, opts)
+		if opts.Continue == "page-2" {
+			return pages[1], nil
+		}
+		return pages[0], nil
+	}
+
+	input := &K8sInput{PageSize: 2}
+	objects, err := input.listPages(context.Background(), pageFunc, metav1.ListOptions{LabelSelector: "app=test"})
+	require.NoError(t, err)
+	require.Len(t, objects, 3)
+	require.Equal(t, []metav1.ListOptions{
+		{LabelSelector: "app=test", Limit: 2},
+		{LabelSelector: "app=test", Limit: 2, Continue: "page-2"},
+	}, requests)
+}
+
+func TestListTasks(t *testing.T) {
+	tasks := []*resourceTask{
+		{gv: daemonsetsgvr.GroupVersion(), resource: metav1.APIResource{Name: "daemonsets", Kind: "DaemonSet", Namespaced: true}},
+		{gv: namespacesgvr.GroupVersion(), resource: metav1.APIResource{Name: "namespaces", Kind: "Namespace"}},
+	}
+
+	input := &K8sInput{Client: newListClient(), Concurrency: 2}
+	require.NoError(t, input.listTasks(context.Background(), tasks))
+	require.Len(t, tasks[0].objects, 3)
+	require.Len(t, tasks[1].objects, 3)
+
+	t.Run("tasks with fields should list the whole objects", func(t *testing.T) {
+		fieldTasks := []*resourceTask{
+			{gv: daemonsetsgvr.GroupVersion(), resource: metav1.APIResource{Name: "daemonsets", Kind: "DaemonSet", Namespaced: true}, fields: true},
+		}
+		input := &K8sInput{Client: newListClient()}
+		require.NoError(t, input.listTasks(context.Background(), fieldTasks))
+		require.Len(t, fieldTasks[0].objects, 3)
+		require.IsType(t, &unstructured.Unstructured{}, fieldTasks[0].objects[0])
+	})
+
+	t.Run("errors should be returned", func(t *testing.T) {
+		client := newListClient()
+		client.PrependReactor("list", "namespaces", func(_ clienttesting.Action) (bool, runtime.Object, error) {
+			return true, nil, apierrors.NewInternalError(errors.New("failure"))
+		})
+		input := &K8sInput{Client: client}
+		require.Error(t, input.listTasks(context.Background(), tasks))
+	})
+
+	t.Run("canceled context should stop the listing", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		input := &K8sInput{Client: newListClient()}
+		require.ErrorIs(t, input.listTasks(ctx, tasks), context.Canceled)
+	})
+}
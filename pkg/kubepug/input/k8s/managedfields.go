@@ -0,0 +1,59 @@
+package k8sinput
+
+import (
+	"context"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	fileinput "github.com/kubepug/kubepug/pkg/kubepug/input/file"
+	"github.com/kubepug/kubepug/pkg/results"
+)
+
+// addManagedFields records, for each object, the managers that wrote it using an API version different
+// from the one the object was listed with. Even when the object was already converted by the API Server,
+// the managedFields keep the apiVersion used by each writer, telling who still uses an old API
+func addManagedFields(managedItems fileinput.FileItems, gv schema.GroupVersion, kind string, objects []metav1.Object) {
+	listedVersion := gv.String()
+	for i := range objects {
+		// The same manager may have more than one entry, like one for the object and other for the status
+		seen := make(map[string]struct{})
+		for _, entry := range objects[i].GetManagedFields() {
+			if entry.APIVersion == "" || entry.APIVersion == listedVersion {
+				continue
+			}
+			key := entry.Manager + "/" + entry.APIVersion
+			if _, ok := seen[key]; ok {
+				continue
+			}
+			seen[key] = struct{}{}
+
+			item := results.ObjectItem(objects[i].GetName(), objects[i].GetNamespace())
+			item.FieldManager = entry.Manager
+			managedItems.AddItem(entry.APIVersion, kind, item)
+		}
+	}
+}
+
+// getManagedFieldsDeprecations compares the API versions used by the field managers with Kubepug store
+func (f *K8sInput) getManagedFieldsDeprecations(ctx context.Context, managedItems fileinput.FileItems) (deprecated, deleted []results.ResultItem, err error) {
+	managedInput := fileinput.NewFileInputFromItems(managedItems, f.Store)
+	managedInput.IgnoreExactGroup = f.IgnoreExactGroup
+	managedInput.IncludePrefixGroup = f.IncludePrefixGroup
+
+	return managedInput.GetDeprecations(ctx)
+}
+
+// isListable verifies if a resource supports being listed. Subresources are never listable
+func isListable(resource *metav1.APIResource) bool {
+	if strings.Contains(resource.Name, "/") {
+		return false
+	}
+	for _, verb := range resource.Verbs {
+		if verb == "list" {
+			return true
+		}
+	}
+	return false
+}
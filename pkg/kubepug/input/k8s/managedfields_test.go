@@ -0,0 +1,75 @@
+package k8sinput
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	fileinput "github.com/kubepug/kubepug/pkg/kubepug/input/file"
+	"github.com/kubepug/kubepug/pkg/store/generatedstore"
+	"github.com/kubepug/kubepug/pkg/store/mock"
+)
+
+func newManagedObject(name, namespace string, managedFields []metav1.ManagedFieldsEntry) metav1.Object {
+	obj := &unstructured.Unstructured{}
+	obj.SetName(name)
+	obj.SetNamespace(namespace)
+	obj.SetManagedFields(managedFields)
+	return obj
+}
+
+func TestAddManagedFields(t *testing.T) {
+	objects := []metav1.Object{
+		newManagedObject("app", "apps", []metav1.ManagedFieldsEntry{
+			{Manager: "kubectl-client-side-apply", APIVersion: "extensions/v1beta1", Operation: metav1.ManagedFieldsOperationUpdate},
+			{Manager: "kubectl-client-side-apply", APIVersion: "extensions/v1beta1", Operation: metav1.ManagedFieldsOperationUpdate, Subresource: "status"},
+			{Manager: "kube-controller-manager", APIVersion: "apps/v1", Operation: metav1.ManagedFieldsOperationUpdate},
+		}),
+		newManagedObject("other", "apps", []metav1.ManagedFieldsEntry{
+			{Manager: "argocd", APIVersion: "apps/v1", Operation: metav1.ManagedFieldsOperationApply},
+		}),
+	}
+
+	managedItems := make(fileinput.FileItems)
+	addManagedFields(managedItems, schema.GroupVersion{Group: "apps", Version: "v1"}, "DaemonSet", objects)
+
+	require.Len(t, managedItems, 1)
+	items := managedItems["extensions/v1beta1/DaemonSet"]
+	require.Len(t, items, 1)
+	require.Equal(t, "app", items[0].ObjectName)
+	require.Equal(t, "apps", items[0].Namespace)
+	require.Equal(t, "kubectl-client-side-apply", items[0].FieldManager)
+}
+
+func TestGetManagedFieldsDeprecations(t *testing.T) {
+	storer, err := generatedstore.NewGeneratedStoreFromBytes([]byte(mock.MockValidData), generatedstore.StoreConfig{})
+	require.NoError(t, err)
+
+	objects := []metav1.Object{
+		newManagedObject("app", "apps", []metav1.ManagedFieldsEntry{
+			{Manager: "helm", APIVersion: "extensions/v1beta1", Operation: metav1.ManagedFieldsOperationUpdate},
+		}),
+	}
+
+	managedItems := make(fileinput.FileItems)
+	addManagedFields(managedItems, schema.GroupVersion{Group: "apps", Version: "v1"}, "DaemonSet", objects)
+
+	input := &K8sInput{Store: storer}
+	deprecated, deleted, err := input.getManagedFieldsDeprecations(context.Background(), managedItems)
+	require.NoError(t, err)
+	require.Empty(t, deprecated)
+	require.Len(t, deleted, 1)
+	require.Equal(t, "DaemonSet", deleted[0].Kind)
+	require.Len(t, deleted[0].Items, 1)
+	require.Equal(t, "helm", deleted[0].Items[0].FieldManager)
+}
+
+func TestIsListable(t *testing.T) {
+	require.True(t, isListable(&metav1.APIResource{Name: "deployments", Verbs: []string{"get", "list"}}))
+	require.False(t, isListable(&metav1.APIResource{Name: "deployments/status", Verbs: []string{"get", "list"}}))
+	require.False(t, isListable(&metav1.APIResource{Name: "tokenreviews", Verbs: []string{"create"}}))
+}
@@ -0,0 +1,354 @@
+package k8sinput
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/version"
+
+	apis "github.com/kubepug/kubepug/pkg/apis/v1alpha1"
+	"github.com/kubepug/kubepug/pkg/errors"
+	"github.com/kubepug/kubepug/pkg/results"
+	"github.com/kubepug/kubepug/pkg/store"
+	"github.com/kubepug/kubepug/pkg/utils"
+)
+
+var (
+	validatingwebhookgvr = schema.GroupVersionResource{
+		Group:    "admissionregistration.k8s.io",
+		Version:  "v1",
+		Resource: "validatingwebhookconfigurations",
+	}
+	mutatingwebhookgvr = schema.GroupVersionResource{
+		Group:    "admissionregistration.k8s.io",
+		Version:  "v1",
+		Resource: "mutatingwebhookconfigurations",
+	}
+	rolegvr = schema.GroupVersionResource{
+		Group:    "rbac.authorization.k8s.io",
+		Version:  "v1",
+		Resource: "roles",
+	}
+	clusterrolegvr = schema.GroupVersionResource{
+		Group:    "rbac.authorization.k8s.io",
+		Version:  "v1",
+		Resource: "clusterroles",
+	}
+)
+
+// apiRule is a reference to APIs made by a rule. An empty list of versions means
+// all the versions of the group, as RBAC rules don't have versions
+type apiRule struct {
+	groups    []string
+	versions  []string
+	resources []string
+}
+
+// ruleMatch is a deprecated or deleted API referenced by a rule
+type ruleMatch struct {
+	gvk    apis.GroupVersionKind
+	status apis.APIVersionStatus
+}
+
+// rulesLookup contains the store interfaces required to resolve the APIs referenced by rules
+type rulesLookup struct {
+	store    store.DefinitionStorer
+	resolver store.KindResolver
+	lister   store.APILister
+}
+
+// getRulesDeprecations walks the rules of webhook configurations, RBAC roles and APIServices, reporting
+// the ones referencing deprecated or deleted APIs. These objects keep working after an upgrade, but
+// stop matching what they were written for
+func (f *K8sInput) getRulesDeprecations(ctx context.Context) (deprecated, deleted []results.ResultItem, err error) {
+	resolver, okResolver := f.Store.(store.KindResolver)
+	lister, okLister := f.Store.(store.APILister)
+	if !okResolver || !okLister {
+		return nil, nil, fmt.Errorf("the store is not able to resolve the APIs referenced by rules")
+	}
+	lookup := &rulesLookup{store: f.Store, resolver: resolver, lister: lister}
+
+	for _, gvr := range []schema.GroupVersionResource{validatingwebhookgvr, mutatingwebhookgvr} {
+		objects, err := f.listRuleObjects(ctx, gvr)
+		if err != nil {
+			return deprecated, deleted, err
+		}
+		for i := range objects {
+			dep, del, err := f.getWebhookDeprecations(ctx, lookup, &objects[i])
+			if err != nil {
+				return deprecated, deleted, err
+			}
+			deprecated = append(deprecated, dep...)
+			deleted = append(deleted, del...)
+		}
+	}
+
+	for _, gvr := range []schema.GroupVersionResource{rolegvr, clusterrolegvr} {
+		objects, err := f.listRuleObjects(ctx, gvr)
+		if err != nil {
+			return deprecated, deleted, err
+		}
+		for i := range objects {
+			dep, del, err := f.getRoleDeprecations(ctx, lookup, &objects[i])
+			if err != nil {
+				return deprecated, deleted, err
+			}
+			deprecated = append(deprecated, dep...)
+			deleted = append(deleted, del...)
+		}
+	}
+
+	apiServices, err := f.listRuleObjects(ctx, apisvcgvr)
+	if err != nil {
+		return deprecated, deleted, err
+	}
+	for i := range apiServices {
+		dep, del, err := getAPIServiceDeprecations(ctx, lookup, &apiServices[i])
+		if err != nil {
+			return deprecated, deleted, err
+		}
+		deprecated = append(deprecated, dep...)
+		deleted = append(deleted, del...)
+	}
+
+	return results.MergeResultItems(deprecated), results.MergeResultItems(deleted), nil
+}
+
+func (f *K8sInput) listRuleObjects(ctx context.Context, gvr schema.GroupVersionResource) ([]unstructured.Unstructured, error) {
+	list, err := f.Client.Resource(gvr).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		if apierrors.IsForbidden(err) || apierrors.IsNotFound(err) {
+			logrus.Warningf("unable to list %s, their rules won't be verified: %s", gvr.Resource, err)
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to list %s: %w", gvr.Resource, err)
+	}
+	return list.Items, nil
+}
+
+func (f *K8sInput) getWebhookDeprecations(ctx context.Context, lookup *rulesLookup, obj *unstructured.Unstructured) (deprecated, deleted []results.ResultItem, err error) {
+	webhooks, _, _ := unstructured.NestedSlice(obj.Object, "webhooks")
+	for i, webhook := range webhooks {
+		webhookMap, ok := webhook.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		rules, _, _ := unstructured.NestedSlice(webhookMap, "rules")
+		for j, rule := range rules {
+			ruleMap, ok := rule.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			r := apiRule{
+				groups:    nestedStrings(ruleMap, "apiGroups"),
+				versions:  nestedStrings(ruleMap, "apiVersions"),
+				resources: nestedStrings(ruleMap, "resources"),
+			}
+			// Webhooks always declare the versions, an empty list would match nothing
+			if len(r.versions) == 0 {
+				continue
+			}
+
+			ruleIndex := fmt.Sprintf("%s webhooks[%d].rules[%d]", obj.GetKind(), i, j)
+			matches, err := f.resolveRule(ctx, lookup, r)
+			if err != nil {
+				return deprecated, deleted, err
+			}
+			dep, del := ruleResults(matches, obj, ruleIndex)
+			deprecated = append(deprecated, dep...)
+			deleted = append(deleted, del...)
+		}
+	}
+	return deprecated, deleted, nil
+}
+
+func (f *K8sInput) getRoleDeprecations(ctx context.Context, lookup *rulesLookup, obj *unstructured.Unstructured) (deprecated, deleted []results.ResultItem, err error) {
+	rules, _, _ := unstructured.NestedSlice(obj.Object, "rules")
+	for i, rule := range rules {
+		ruleMap, ok := rule.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		r := apiRule{
+			groups:    nestedStrings(ruleMap, "apiGroups"),
+			resources: nestedStrings(ruleMap, "resources"),
+		}
+
+		ruleIndex := fmt.Sprintf("%s rules[%d]", obj.GetKind(), i)
+		matches, err := f.resolveRule(ctx, lookup, r)
+		if err != nil {
+			return deprecated, deleted, err
+		}
+		dep, del := ruleResults(matches, obj, ruleIndex)
+		deprecated = append(deprecated, dep...)
+		deleted = append(deleted, del...)
+	}
+	return deprecated, deleted, nil
+}
+
+// getAPIServiceDeprecations reports the APIServices of a group/version whose APIs are all deprecated or
+// deleted. Local APIServices are skipped, as they are managed by the API Server itself
+func getAPIServiceDeprecations(ctx context.Context, lookup *rulesLookup, obj *unstructured.Unstructured) (deprecated, deleted []results.ResultItem, err error) {
+	if _, found, _ := unstructured.NestedMap(obj.Object, "spec", "service"); !found {
+		return nil, nil, nil
+	}
+	group, _, _ := unstructured.NestedString(obj.Object, "spec", "group")
+	apiVersion, _, _ := unstructured.NestedString(obj.Object, "spec", "version")
+	if apiVersion == "" {
+		return nil, nil, nil
+	}
+
+	apiList, err := lookup.lister.ListAPIs(ctx, group)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	matches := make([]ruleMatch, 0)
+	for _, gvk := range apiList {
+		if gvk.Version != apiVersion {
+			continue
+		}
+		status, err := lookup.store.GetAPIDefinition(ctx, gvk.Group, gvk.Version, gvk.Kind)
+		if err != nil && !errors.IsErrAPINotFound(err) {
+			return nil, nil, err
+		}
+		// A single API still valid means the APIService is still needed
+		if status.DeprecationVersion == "" && status.DeletedVersion == "" {
+			return nil, nil, nil
+		}
+		matches = append(matches, ruleMatch{gvk: gvk, status: status})
+	}
+
+	deprecated, deleted = ruleResults(matches, obj, "APIService spec")
+	return deprecated, deleted, nil
+}
+
+// resolveRule finds the deprecated and deleted APIs referenced by a rule. When the rule matches
+// all the versions, the API is only reported if none of its versions is still valid
+func (f *K8sInput) resolveRule(ctx context.Context, lookup *rulesLookup, rule apiRule) ([]ruleMatch, error) {
+	matches := make([]ruleMatch, 0)
+	for _, group := range rule.groups {
+		if group == "*" || !utils.ShouldParse(group, f.IgnoreExactGroup, f.IncludePrefixGroup) {
+			continue
+		}
+
+		seen := make(map[string]struct{})
+		for _, resource := range rule.resources {
+			// Subresources (like deployments/scale) are verified using the resource
+			resource, _, _ = strings.Cut(resource, "/")
+			if _, ok := seen[resource]; ok || resource == "*" || resource == "" {
+				continue
+			}
+			seen[resource] = struct{}{}
+
+			allVersions := len(rule.versions) == 0 || contains(rule.versions, "*")
+			versions := rule.versions
+			if allVersions {
+				var err error
+				versions, err = groupVersions(ctx, lookup.lister, group)
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			resourceMatches, valid, err := resolveResource(ctx, lookup, group, resource, versions)
+			if err != nil {
+				return nil, err
+			}
+			if allVersions {
+				if valid || len(resourceMatches) == 0 {
+					continue
+				}
+				// Only the most recent version is reported, as all of them are deprecated
+				resourceMatches = resourceMatches[len(resourceMatches)-1:]
+			}
+			matches = append(matches, resourceMatches...)
+		}
+	}
+	return matches, nil
+}
+
+// resolveResource returns the deprecated and deleted versions of a resource, sorted from the oldest
+// to the newest one, and if any of the versions is still valid
+func resolveResource(ctx context.Context, lookup *rulesLookup, group, resource string, versions []string) (matches []ruleMatch, valid bool, err error) {
+	for _, v := range versions {
+		kind, err := lookup.resolver.GetKindForResource(ctx, group, v, resource)
+		if err != nil {
+			if errors.IsErrAPINotFound(err) {
+				continue
+			}
+			return nil, false, err
+		}
+		status, err := lookup.store.GetAPIDefinition(ctx, group, v, kind)
+		if err != nil && !errors.IsErrAPINotFound(err) {
+			return nil, false, err
+		}
+		if status.DeprecationVersion == "" && status.DeletedVersion == "" {
+			valid = true
+			continue
+		}
+		matches = append(matches, ruleMatch{gvk: apis.GroupVersionKind{Group: group, Version: v, Kind: kind}, status: status})
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return version.CompareKubeAwareVersionStrings(matches[i].gvk.Version, matches[j].gvk.Version) < 0
+	})
+	return matches, valid, nil
+}
+
+// groupVersions returns all the versions of a group known by the store
+func groupVersions(ctx context.Context, lister store.APILister, group string) ([]string, error) {
+	apiList, err := lister.ListAPIs(ctx, group)
+	if err != nil {
+		return nil, err
+	}
+	versions := make([]string, 0)
+	for _, gvk := range apiList {
+		if !contains(versions, gvk.Version) {
+			versions = append(versions, gvk.Version)
+		}
+	}
+	return versions, nil
+}
+
+func ruleResults(matches []ruleMatch, obj *unstructured.Unstructured, rule string) (deprecated, deleted []results.ResultItem) {
+	for _, match := range matches {
+		item := results.ObjectItem(obj.GetName(), obj.GetNamespace())
+		item.Rule = rule
+
+		result := results.CreateItem(match.gvk.Group, match.gvk.Version, match.gvk.Kind, []results.Item{item})
+		result.Description = match.status.Description
+		result.Replacement = match.status.Replacement
+		result.ReplacementAvailable = match.status.ReplacementAvailable
+		result.K8sVersion = match.status.DeprecationVersion
+
+		if match.status.DeletedVersion != "" {
+			result.K8sVersion = match.status.DeletedVersion
+			deleted = append(deleted, result)
+			continue
+		}
+		deprecated = append(deprecated, result)
+	}
+	return deprecated, deleted
+}
+
+func nestedStrings(obj map[string]interface{}, field string) []string {
+	values, _, _ := unstructured.NestedStringSlice(obj, field)
+	return values
+}
+
+func contains(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
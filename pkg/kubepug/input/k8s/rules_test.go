@@ -0,0 +1,132 @@
+package k8sinput
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	"github.com/kubepug/kubepug/pkg/store/generatedstore"
+	"github.com/kubepug/kubepug/pkg/store/mock"
+)
+
+func newRuleObject(apiVersion, kind, name, namespace string, fields map[string]interface{}) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: fields}
+	obj.SetAPIVersion(apiVersion)
+	obj.SetKind(kind)
+	obj.SetName(name)
+	obj.SetNamespace(namespace)
+	return obj
+}
+
+// rulesData adds to the mock data an API with deprecated and valid versions
+const rulesData = `[
+	{"group": "flowcontrol.k8s.io", "version": "v1beta1", "kind": "FlowSchema", "deprecated_version": {"version_major": 1, "version_minor": 26}},
+	{"group": "flowcontrol.k8s.io", "version": "v1", "kind": "FlowSchema"},
+`
+
+func TestGetRulesDeprecations(t *testing.T) {
+	storer, err := generatedstore.NewGeneratedStoreFromBytes([]byte(rulesData+strings.TrimPrefix(strings.TrimSpace(mock.MockValidData), "[")), generatedstore.StoreConfig{})
+	require.NoError(t, err)
+
+	objects := []runtime.Object{
+		newRuleObject("admissionregistration.k8s.io/v1", "ValidatingWebhookConfiguration", "policy", "", map[string]interface{}{
+			"webhooks": []interface{}{
+				map[string]interface{}{
+					"name": "pods.policy.example.com",
+					"rules": []interface{}{
+						map[string]interface{}{"apiGroups": []interface{}{""}, "apiVersions": []interface{}{"v1"}, "resources": []interface{}{"pods"}},
+						map[string]interface{}{"apiGroups": []interface{}{"extensions"}, "apiVersions": []interface{}{"v1beta1"}, "resources": []interface{}{"daemonsets", "daemonsets/status"}},
+					},
+				},
+			},
+		}),
+		newRuleObject("admissionregistration.k8s.io/v1", "MutatingWebhookConfiguration", "everything", "", map[string]interface{}{
+			"webhooks": []interface{}{
+				map[string]interface{}{
+					"name": "all.example.com",
+					"rules": []interface{}{
+						map[string]interface{}{"apiGroups": []interface{}{"*"}, "apiVersions": []interface{}{"*"}, "resources": []interface{}{"*"}},
+					},
+				},
+			},
+		}),
+		newRuleObject("rbac.authorization.k8s.io/v1", "Role", "legacy", "apps", map[string]interface{}{
+			"rules": []interface{}{
+				map[string]interface{}{"apiGroups": []interface{}{"apps"}, "resources": []interface{}{"deployments"}, "verbs": []interface{}{"get"}},
+				map[string]interface{}{"apiGroups": []interface{}{"", "extensions"}, "resources": []interface{}{"blahpods", "daemonsets"}, "verbs": []interface{}{"get"}},
+			},
+		}),
+		newRuleObject("rbac.authorization.k8s.io/v1", "ClusterRole", "discovery", "", map[string]interface{}{
+			"rules": []interface{}{
+				// FlowSchema has a valid version, so the rule is still valid
+				map[string]interface{}{"apiGroups": []interface{}{"flowcontrol.k8s.io"}, "resources": []interface{}{"flowschemas"}, "verbs": []interface{}{"get"}},
+			},
+		}),
+		newRuleObject("apiregistration.k8s.io/v1", "APIService", "v1beta1.extensions", "", map[string]interface{}{
+			"spec": map[string]interface{}{"group": "extensions", "version": "v1beta1"},
+		}),
+	}
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		validatingwebhookgvr: "ValidatingWebhookConfigurationList",
+		mutatingwebhookgvr:   "MutatingWebhookConfigurationList",
+		rolegvr:              "RoleList",
+		clusterrolegvr:       "ClusterRoleList",
+		apisvcgvr:            "APIServiceList",
+	}, objects...)
+
+	input := &K8sInput{
+		Store:              storer,
+		Client:             client,
+		IncludePrefixGroup: []string{".k8s.io"},
+	}
+
+	deprecated, deleted, err := input.getRulesDeprecations(context.Background())
+	require.NoError(t, err)
+	require.Empty(t, deprecated)
+	require.Len(t, deleted, 2)
+
+	require.Equal(t, "DaemonSet", deleted[0].Kind)
+	rules := make([]string, 0)
+	for _, item := range deleted[0].Items {
+		rules = append(rules, item.ObjectName+" "+item.Rule)
+	}
+	require.Equal(t, []string{
+		"policy ValidatingWebhookConfiguration webhooks[0].rules[1]",
+		"legacy Role rules[1]",
+	}, rules)
+
+	require.Equal(t, "BlahPod", deleted[1].Kind)
+	require.Len(t, deleted[1].Items, 1)
+	require.Equal(t, "apps", deleted[1].Items[0].Namespace)
+}
+
+func TestGetAPIServiceDeprecations(t *testing.T) {
+	storer, err := generatedstore.NewGeneratedStoreFromBytes([]byte(mock.MockValidData), generatedstore.StoreConfig{})
+	require.NoError(t, err)
+	lookup := &rulesLookup{store: storer, resolver: storer, lister: storer}
+
+	aggregated := newRuleObject("apiregistration.k8s.io/v1", "APIService", "v1beta1.extensions", "", map[string]interface{}{
+		"spec": map[string]interface{}{
+			"group":   "extensions",
+			"version": "v1beta1",
+			"service": map[string]interface{}{"name": "extensions-api", "namespace": "kube-system"},
+		},
+	})
+	_, deleted, err := getAPIServiceDeprecations(context.Background(), lookup, aggregated)
+	require.NoError(t, err)
+	require.Len(t, deleted, 1)
+	require.Equal(t, "APIService spec", deleted[0].Items[0].Rule)
+
+	local := newRuleObject("apiregistration.k8s.io/v1", "APIService", "v1beta1.extensions", "", map[string]interface{}{
+		"spec": map[string]interface{}{"group": "extensions", "version": "v1beta1"},
+	})
+	_, deleted, err = getAPIServiceDeprecations(context.Background(), lookup, local)
+	require.NoError(t, err)
+	require.Empty(t, deleted)
+}
@@ -0,0 +1,92 @@
+package k8sinput
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/kubepug/kubepug/pkg/results"
+	"github.com/kubepug/kubepug/pkg/store/crdstore"
+)
+
+const (
+	storedVersionDeprecated = "deprecated"
+	storedVersionNotServed  = "not served"
+	storedVersionRemoved    = "not declared on spec.versions"
+)
+
+var crdgvr = schema.GroupVersionResource{
+	Group:    "apiextensions.k8s.io",
+	Version:  "v1",
+	Resource: "customresourcedefinitions",
+}
+
+// GetStoredVersions verifies the CustomResourceDefinitions of the cluster, returning the ones whose
+// status.storedVersions contains a version that is deprecated, not served or not declared anymore.
+// Removing such version from the CRD before migrating the objects and pruning storedVersions fails
+func (f *K8sInput) GetStoredVersions(ctx context.Context) ([]results.StoredVersionItem, error) {
+	crds, err := f.Client.Resource(crdgvr).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		if apierrors.IsForbidden(err) || apierrors.IsNotFound(err) {
+			logrus.Warningf("unable to list the CustomResourceDefinitions, their stored versions won't be verified: %s", err)
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to list the CustomResourceDefinitions: %w", err)
+	}
+
+	items := make([]results.StoredVersionItem, 0)
+	for i := range crds.Items {
+		items = append(items, getCRDStoredVersions(&crds.Items[i])...)
+	}
+	return items, nil
+}
+
+func getCRDStoredVersions(crd *unstructured.Unstructured) []results.StoredVersionItem {
+	storedVersions, _, _ := unstructured.NestedStringSlice(crd.Object, "status", "storedVersions")
+	if len(storedVersions) == 0 {
+		return nil
+	}
+
+	group, _, _ := unstructured.NestedString(crd.Object, "spec", "group")
+	kind, _, _ := unstructured.NestedString(crd.Object, "spec", "names", "kind")
+
+	versions := make(map[string]crdstore.Version)
+	storageVersion := ""
+	for _, v := range crdstore.GetVersions(crd) {
+		versions[v.Name] = v
+		if v.Storage {
+			storageVersion = v.Name
+		}
+	}
+
+	items := make([]results.StoredVersionItem, 0)
+	for _, stored := range storedVersions {
+		var reason string
+		v, ok := versions[stored]
+		switch {
+		case !ok:
+			reason = storedVersionRemoved
+		case !v.Served:
+			reason = storedVersionNotServed
+		case v.Deprecated:
+			reason = storedVersionDeprecated
+		default:
+			continue
+		}
+
+		items = append(items, results.StoredVersionItem{
+			CRD:            crd.GetName(),
+			Group:          group,
+			Kind:           kind,
+			Version:        stored,
+			StorageVersion: storageVersion,
+			Reason:         reason,
+		})
+	}
+	return items
+}
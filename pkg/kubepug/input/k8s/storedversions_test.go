@@ -0,0 +1,63 @@
+package k8sinput
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	"github.com/kubepug/kubepug/pkg/results"
+)
+
+func newStoredVersionsCRD(name string, versions []interface{}, storedVersions []interface{}) *unstructured.Unstructured {
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "apiextensions.k8s.io/v1",
+			"kind":       "CustomResourceDefinition",
+			"metadata": map[string]interface{}{
+				"name": name,
+			},
+			"spec": map[string]interface{}{
+				"group": "example.com",
+				"names": map[string]interface{}{
+					"kind":   "Widget",
+					"plural": "widgets",
+				},
+				"versions": versions,
+			},
+			"status": map[string]interface{}{
+				"storedVersions": storedVersions,
+			},
+		},
+	}
+}
+
+func TestGetStoredVersions(t *testing.T) {
+	objects := []runtime.Object{
+		newStoredVersionsCRD("widgets.example.com", []interface{}{
+			map[string]interface{}{"name": "v1alpha1", "served": false, "storage": false},
+			map[string]interface{}{"name": "v1beta1", "served": true, "storage": false, "deprecated": true},
+			map[string]interface{}{"name": "v1", "served": true, "storage": true},
+		}, []interface{}{"v1alpha0", "v1alpha1", "v1beta1", "v1"}),
+		newStoredVersionsCRD("migrated.example.com", []interface{}{
+			map[string]interface{}{"name": "v1beta1", "served": true, "storage": false, "deprecated": true},
+			map[string]interface{}{"name": "v1", "served": true, "storage": true},
+		}, []interface{}{"v1"}),
+	}
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		crdgvr: "CustomResourceDefinitionList",
+	}, objects...)
+
+	input := &K8sInput{Client: client}
+	items, err := input.GetStoredVersions(context.Background())
+	require.NoError(t, err)
+	require.ElementsMatch(t, []results.StoredVersionItem{
+		{CRD: "widgets.example.com", Group: "example.com", Kind: "Widget", Version: "v1alpha0", StorageVersion: "v1", Reason: storedVersionRemoved},
+		{CRD: "widgets.example.com", Group: "example.com", Kind: "Widget", Version: "v1alpha1", StorageVersion: "v1", Reason: storedVersionNotServed},
+		{CRD: "widgets.example.com", Group: "example.com", Kind: "Widget", Version: "v1beta1", StorageVersion: "v1", Reason: storedVersionDeprecated},
+	}, items)
+}
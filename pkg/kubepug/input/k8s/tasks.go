@@ -0,0 +1,61 @@
+package k8sinput
+
+import (
+	"context"
+
+	"golang.org/x/sync/errgroup"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	apis "github.com/kubepug/kubepug/pkg/apis/v1alpha1"
+)
+
+// defaultConcurrency is how many resources are listed at the same time when Concurrency is not set
+const defaultConcurrency = 4
+
+// resourceTask is a resource whose objects must be listed
+type resourceTask struct {
+	gv         schema.GroupVersion
+	resource   metav1.APIResource
+	apiResult  apis.APIVersionStatus
+	deprecated bool
+	// fields means the resource has deprecated fields, so the whole objects are listed
+	fields  bool
+	objects []metav1.Object
+}
+
+// listTasks lists the objects of the resources, using up to Concurrency workers. The first
+// error cancels the tasks being listed and stops the remaining ones from being started
+func (f *K8sInput) listTasks(ctx context.Context, tasks []*resourceTask) error {
+	concurrency := f.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultConcurrency
+	}
+
+	group, ctx := errgroup.WithContext(ctx)
+	group.SetLimit(concurrency)
+	for _, task := range tasks {
+		group.Go(func() error {
+			gvr := task.gv.WithResource(task.resource.Name)
+			if !task.fields {
+				objects, err := f.listMetadata(ctx, gvr, task.resource.Namespaced, f.selectorOptions())
+				if err != nil {
+					return err
+				}
+				task.objects = objects
+				return nil
+			}
+
+			objects, err := f.listObjects(ctx, gvr, task.resource.Namespaced, f.selectorOptions())
+			if err != nil {
+				return err
+			}
+			task.objects = make([]metav1.Object, 0, len(objects))
+			for i := range objects {
+				task.objects = append(task.objects, &objects[i])
+			}
+			return nil
+		})
+	}
+	return group.Wait()
+}
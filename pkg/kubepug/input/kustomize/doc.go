@@ -0,0 +1,5 @@
+// Package kustomizeinput contains the methods used to get Deprecated objects
+// from a Kustomization, building it before the analysis
+package kustomizeinput
+
+// import "github.com/kubepug/kubepug/pkg/kubepug/input/kustomize"
@@ -0,0 +1,87 @@
+package kustomizeinput
+
+import (
+	"fmt"
+	"path"
+
+	log "github.com/sirupsen/logrus"
+	"sigs.k8s.io/kustomize/api/krusty"
+	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+
+	fileinput "github.com/kubepug/kubepug/pkg/kubepug/input/file"
+	"github.com/kubepug/kubepug/pkg/store"
+)
+
+// Config defines the kustomization that should be built
+type Config struct {
+	// Path is the location of the directory containing the kustomization file
+	Path string
+	// LoadRestrictionsNone allows the kustomization to load files outside of its root,
+	// the same as kustomize --load-restrictor=LoadRestrictionsNone
+	LoadRestrictionsNone bool
+	// Fields enables the verification of deprecated and deleted fields on the built resources
+	Fields bool
+}
+
+// KustomizeInput defines a struct that will be used when comparing APIs against a built Kustomization
+type KustomizeInput struct {
+	*fileinput.FileInput
+}
+
+// NewKustomizeInput builds the kustomization and returns the struct KustomizeInput populated
+// with the resulting resources
+func NewKustomizeInput(config Config, storer store.DefinitionStorer) (*KustomizeInput, error) {
+	input := fileinput.NewFileInputFromItems(make(fileinput.FileItems), storer)
+	input.Fields = config.Fields
+	if err := addKustomizationManifests(config, input.AddManifests); err != nil {
+		return nil, err
+	}
+
+	return &KustomizeInput{FileInput: input}, nil
+}
+
+// GetKustomizationItems builds the kustomization and converts the resulting resources into a map
+// of Items. The location of each item is the kustomization path, or the file that originated the resource
+// when the kustomization enables the originAnnotations build metadata
+func GetKustomizationItems(config Config) (fileinput.FileItems, error) {
+	fileItems := make(fileinput.FileItems)
+	if err := addKustomizationManifests(config, fileItems.AddManifests); err != nil {
+		return nil, err
+	}
+	return fileItems, nil
+}
+
+// addKustomizationManifests builds the kustomization, adding the manifest of each resulting resource
+func addKustomizationManifests(config Config, add func(manifest []byte, location string)) error {
+	if config.Path == "" {
+		return fmt.Errorf("kustomization location cannot be empty")
+	}
+
+	options := krusty.MakeDefaultOptions()
+	if config.LoadRestrictionsNone {
+		options.LoadRestrictions = types.LoadRestrictionsNone
+	}
+
+	resMap, err := krusty.MakeKustomizer(options).Run(filesys.MakeFsOnDisk(), config.Path)
+	if err != nil {
+		return fmt.Errorf("failed to build kustomization %s: %w", config.Path, err)
+	}
+
+	for _, res := range resMap.Resources() {
+		manifest, err := res.AsYAML()
+		if err != nil {
+			log.Warningf("failed to convert resource %s to yaml, skipping: %s", res.CurId(), err)
+			continue
+		}
+
+		location := config.Path
+		if origin, err := res.GetOrigin(); err == nil && origin != nil && origin.Path != "" && origin.Repo == "" {
+			location = path.Join(config.Path, origin.Path)
+		}
+
+		add(manifest, location)
+	}
+
+	return nil
+}
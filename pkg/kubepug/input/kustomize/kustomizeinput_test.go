@@ -0,0 +1,59 @@
+package kustomizeinput
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubepug/kubepug/pkg/store/generatedstore"
+	"github.com/kubepug/kubepug/pkg/store/mock"
+)
+
+const (
+	baseKustomization   = "../../../../test/testdata/kustomize/base"
+	legacyKustomization = "../../../../test/testdata/kustomize/overlays/legacy"
+)
+
+func TestGetKustomizationItems(t *testing.T) {
+	t.Run("empty kustomization should fail", func(t *testing.T) {
+		_, err := GetKustomizationItems(Config{})
+		require.ErrorContains(t, err, "kustomization location cannot be empty")
+	})
+
+	t.Run("invalid kustomization should fail", func(t *testing.T) {
+		_, err := GetKustomizationItems(Config{Path: "/tmp123/lalala"})
+		require.ErrorContains(t, err, "failed to build kustomization")
+	})
+
+	t.Run("base should be built", func(t *testing.T) {
+		items, err := GetKustomizationItems(Config{Path: baseKustomization})
+		require.NoError(t, err)
+		require.Len(t, items, 2)
+		require.Equal(t, baseKustomization, items["apps/v1/DaemonSet"][0].Location)
+	})
+
+	t.Run("overlay should patch the base", func(t *testing.T) {
+		items, err := GetKustomizationItems(Config{Path: legacyKustomization})
+		require.NoError(t, err)
+		require.NotContains(t, items, "apps/v1/DaemonSet")
+		require.Len(t, items["extensions/v1beta1/DaemonSet"], 1)
+		require.Equal(t, "legacy", items["extensions/v1beta1/DaemonSet"][0].Namespace)
+		require.Equal(t, "../../../../test/testdata/kustomize/base/daemonset.yaml", items["extensions/v1beta1/DaemonSet"][0].Location)
+	})
+}
+
+func TestGetDeprecations(t *testing.T) {
+	storer, err := generatedstore.NewGeneratedStoreFromBytes([]byte(mock.MockValidData), generatedstore.StoreConfig{})
+	require.NoError(t, err)
+
+	input, err := NewKustomizeInput(Config{Path: legacyKustomization}, storer)
+	require.NoError(t, err)
+
+	deprecated, deleted, err := input.GetDeprecations(context.Background())
+	require.NoError(t, err)
+	require.Empty(t, deprecated)
+	require.Len(t, deleted, 1)
+	require.Equal(t, "DaemonSet", deleted[0].Kind)
+	require.Equal(t, "app", deleted[0].Items[0].ObjectName)
+}
@@ -0,0 +1,5 @@
+// Package metricsinput contains the methods used to get the Deprecated APIs
+// that were requested, reading the metrics exposed by the Kubernetes API Server
+package metricsinput
+
+// import "github.com/kubepug/kubepug/pkg/kubepug/input/metrics"
@@ -0,0 +1,220 @@
+package metricsinput
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/prometheus/common/expfmt"
+	"github.com/sirupsen/logrus"
+	"k8s.io/client-go/rest"
+
+	"github.com/kubepug/kubepug/pkg/errors"
+	"github.com/kubepug/kubepug/pkg/results"
+	"github.com/kubepug/kubepug/pkg/store"
+	"github.com/kubepug/kubepug/pkg/utils"
+)
+
+const (
+	// deprecatedAPIsMetric is the gauge set by the API Server when a deprecated API is requested
+	deprecatedAPIsMetric = "apiserver_requested_deprecated_apis"
+	// APIServerLocation is the location reported for the requests found on the API Server metrics endpoint
+	APIServerLocation = "apiserver:/metrics"
+)
+
+// DeprecatedRequest is a deprecated API resource requested to the API Server
+type DeprecatedRequest struct {
+	Group          string
+	Version        string
+	Resource       string
+	Subresource    string
+	RemovedRelease string
+}
+
+// MetricsInput defines a struct that will be used when comparing the APIs requested to the API Server
+type MetricsInput struct {
+	Requests []DeprecatedRequest
+	// Location is where the metrics were read from, being a file or the API Server
+	Location string
+	Store    store.DefinitionStorer
+	// We will have a IncludeGroup and a IgnoreGroup configs to tune false positives and false negatives
+	// If there is an IncludeGroup, only the resources on this group will be parsed
+	IncludePrefixGroup []string
+	// If an API is inside the IgnoreGroup it will be bypassed
+	IgnoreExactGroup []string
+}
+
+// NewMetricsInput returns the struct MetricsInput populated with the requests found on a
+// Prometheus text exposition file. Location can be "-" to read the metrics from STDIN
+func NewMetricsInput(location string, storer store.DefinitionStorer) (*MetricsInput, error) {
+	var reader io.Reader
+	if location == "-" {
+		reader = os.Stdin
+	} else {
+		file, err := os.Open(location)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open metrics file %s: %w", location, err)
+		}
+		defer file.Close()
+		reader = file
+	}
+
+	requests, err := ParseDeprecatedRequests(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	return newMetricsInput(requests, location, storer), nil
+}
+
+// NewMetricsInputFromCluster returns the struct MetricsInput populated with the requests found on
+// the API Server /metrics endpoint. The client can be the RESTClient of the discovery client
+func NewMetricsInputFromCluster(ctx context.Context, client rest.Interface, storer store.DefinitionStorer) (*MetricsInput, error) {
+	data, err := client.Get().AbsPath("/metrics").DoRaw(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the API Server metrics: %w", err)
+	}
+
+	requests, err := ParseDeprecatedRequests(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	return newMetricsInput(requests, APIServerLocation, storer), nil
+}
+
+func newMetricsInput(requests []DeprecatedRequest, location string, storer store.DefinitionStorer) *MetricsInput {
+	return &MetricsInput{
+		Requests:           requests,
+		Location:           location,
+		Store:              storer,
+		IgnoreExactGroup:   store.DefaultIgnoreGroups(),
+		IncludePrefixGroup: store.IncludeGroups(storer, []string{".k8s.io"}),
+	}
+}
+
+// ParseDeprecatedRequests reads a Prometheus text exposition and returns the requests
+// recorded on the apiserver_requested_deprecated_apis metric
+func ParseDeprecatedRequests(reader io.Reader) ([]DeprecatedRequest, error) {
+	parser := expfmt.TextParser{}
+	families, err := parser.TextToMetricFamilies(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse metrics: %w", err)
+	}
+
+	family, ok := families[deprecatedAPIsMetric]
+	if !ok {
+		logrus.Infof("metric %s not found, no deprecated API was requested", deprecatedAPIsMetric)
+		return nil, nil
+	}
+
+	requests := make([]DeprecatedRequest, 0, len(family.GetMetric()))
+	for _, metric := range family.GetMetric() {
+		// The gauge is set to 1 when the API is requested
+		if metric.GetGauge().GetValue() == 0 {
+			continue
+		}
+
+		request := DeprecatedRequest{}
+		for _, label := range metric.GetLabel() {
+			switch label.GetName() {
+			case "group":
+				request.Group = label.GetValue()
+			case "version":
+				request.Version = label.GetValue()
+			case "resource":
+				request.Resource = label.GetValue()
+			case "subresource":
+				request.Subresource = label.GetValue()
+			case "removed_release":
+				request.RemovedRelease = label.GetValue()
+			}
+		}
+		if request.Version == "" || request.Resource == "" {
+			continue
+		}
+		requests = append(requests, request)
+	}
+
+	return requests, nil
+}
+
+// GetDeprecations compares the API resources requested to the API Server with Kubepug store
+// returning the set of Deprecated results
+func (m *MetricsInput) GetDeprecations(ctx context.Context) (deprecated, deleted []results.ResultItem, err error) {
+	resolver, _ := m.Store.(store.KindResolver)
+	comparer, _ := m.Store.(store.VersionComparer)
+
+	for _, request := range m.Requests {
+		if !utils.ShouldParse(request.Group, m.IgnoreExactGroup, m.IncludePrefixGroup) {
+			continue
+		}
+
+		kind := ""
+		if resolver != nil {
+			kind, err = resolver.GetKindForResource(ctx, request.Group, request.Version, request.Resource)
+			if err != nil && !errors.IsErrAPINotFound(err) {
+				return deprecated, deleted, err
+			}
+		}
+
+		resourceName := request.Resource
+		if request.Subresource != "" {
+			resourceName = request.Resource + "/" + request.Subresource
+		}
+		items := []results.Item{results.RequestedItem(resourceName, m.Location)}
+
+		// The API Server knows the request was made to a deprecated API even when our store doesn't
+		// know the resource (like a deprecated CRD version), so it is still reported without a kind,
+		// identified by the resource name of the item. It is deleted when removed on the store version
+		if kind == "" {
+			logrus.Debugf("unable to find the kind of %s/%s/%s, reporting the resource", request.Group, request.Version, request.Resource)
+			result := results.CreateItem(request.Group, request.Version, "", items)
+			if request.RemovedRelease != "" && comparer != nil && comparer.IsReleased(request.RemovedRelease) {
+				result.K8sVersion = request.RemovedRelease
+				result.Description = fmt.Sprintf("Deprecated API requested to the API Server, removed in %s", request.RemovedRelease)
+				deleted = append(deleted, result)
+				continue
+			}
+			result.K8sVersion = "unknown"
+			if request.RemovedRelease != "" {
+				result.Description = fmt.Sprintf("Deprecated API requested to the API Server, to be removed in %s", request.RemovedRelease)
+			}
+			deprecated = append(deprecated, result)
+			continue
+		}
+
+		apiDef, err := m.Store.GetAPIDefinition(ctx, request.Group, request.Version, kind)
+		if err != nil {
+			if !errors.IsErrAPINotFound(err) {
+				return deprecated, deleted, err
+			}
+		}
+
+		if apiDef.DeletedVersion == "" && apiDef.DeprecationVersion == "" {
+			continue
+		}
+
+		result := results.CreateItem(request.Group, request.Version, kind, items)
+		result.Description = apiDef.Description
+
+		if apiDef.Replacement != nil {
+			result.Replacement = apiDef.Replacement
+			result.ReplacementAvailable = apiDef.ReplacementAvailable
+		}
+
+		result.K8sVersion = apiDef.DeprecationVersion
+
+		if apiDef.DeletedVersion != "" {
+			result.K8sVersion = apiDef.DeletedVersion
+			deleted = append(deleted, result)
+			continue
+		}
+		deprecated = append(deprecated, result)
+	}
+
+	// Subresources of the same API are reported as different series, so they are grouped together
+	return results.MergeResultItems(deprecated), results.MergeResultItems(deleted), nil
+}
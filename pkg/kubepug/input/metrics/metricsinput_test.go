@@ -0,0 +1,117 @@
+package metricsinput
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/rest"
+
+	"github.com/kubepug/kubepug/pkg/results"
+	"github.com/kubepug/kubepug/pkg/store/generatedstore"
+	"github.com/kubepug/kubepug/pkg/store/mock"
+)
+
+const metricsFile = "../../../../test/testdata/metrics/metrics.txt"
+
+func TestParseDeprecatedRequests(t *testing.T) {
+	t.Run("invalid metrics should fail", func(t *testing.T) {
+		_, err := ParseDeprecatedRequests(strings.NewReader("apiserver_requested_deprecated_apis{group=\"a\" 1\n"))
+		require.ErrorContains(t, err, "failed to parse metrics")
+	})
+
+	t.Run("missing metric should return nothing", func(t *testing.T) {
+		requests, err := ParseDeprecatedRequests(strings.NewReader("# TYPE up gauge\nup 1\n"))
+		require.NoError(t, err)
+		require.Empty(t, requests)
+	})
+
+	t.Run("metric should be parsed", func(t *testing.T) {
+		file, err := os.Open(metricsFile)
+		require.NoError(t, err)
+		defer file.Close()
+
+		requests, err := ParseDeprecatedRequests(file)
+		require.NoError(t, err)
+		require.Len(t, requests, 6)
+		require.Contains(t, requests, DeprecatedRequest{Group: "extensions", Version: "v1beta1", Resource: "daemonsets", Subresource: "status", RemovedRelease: "1.16"})
+	})
+}
+
+func TestGetDeprecations(t *testing.T) {
+	storer, err := generatedstore.NewGeneratedStoreFromBytes([]byte(mock.MockValidData), generatedstore.StoreConfig{})
+	require.NoError(t, err)
+
+	t.Run("non existing file should fail", func(t *testing.T) {
+		_, err := NewMetricsInput("/tmp/xpto/metrics.txt", storer)
+		require.ErrorContains(t, err, "failed to open metrics file")
+	})
+
+	t.Run("requests from file should be reported", func(t *testing.T) {
+		input, err := NewMetricsInput(metricsFile, storer)
+		require.NoError(t, err)
+
+		deprecated, deleted, err := input.GetDeprecations(context.Background())
+		require.NoError(t, err)
+
+		require.Len(t, deleted, 3)
+		require.Equal(t, "DaemonSet", deleted[0].Kind)
+		require.Equal(t, []results.Item{
+			results.RequestedItem("daemonsets", metricsFile),
+			results.RequestedItem("daemonsets/status", metricsFile),
+		}, deleted[0].Items)
+		require.Equal(t, "BlahPod", deleted[1].Kind)
+
+		// Resources unknown by the store are still reported, as the API Server flagged them, and
+		// are deleted when removed on the store version
+		require.Empty(t, deleted[2].Kind)
+		require.Equal(t, "1.20", deleted[2].K8sVersion)
+		require.Equal(t, []results.Item{results.RequestedItem("gadgets", metricsFile)}, deleted[2].Items)
+
+		require.Len(t, deprecated, 1)
+		require.Empty(t, deprecated[0].Kind)
+		require.Equal(t, "unknown", deprecated[0].K8sVersion)
+		require.Equal(t, []results.Item{results.RequestedItem("widgets", metricsFile)}, deprecated[0].Items)
+	})
+
+	t.Run("resources unknown by the store should be deprecated until their removal", func(t *testing.T) {
+		storer, err := generatedstore.NewGeneratedStoreFromBytes([]byte(mock.MockValidData), generatedstore.StoreConfig{MinVersion: "v1.19"})
+		require.NoError(t, err)
+		input, err := NewMetricsInput(metricsFile, storer)
+		require.NoError(t, err)
+
+		deprecated, _, err := input.GetDeprecations(context.Background())
+		require.NoError(t, err)
+		require.Len(t, deprecated, 2)
+		require.Equal(t, "v1beta1", deprecated[0].Version)
+		require.Equal(t, "unknown", deprecated[0].K8sVersion)
+		require.Equal(t, "Deprecated API requested to the API Server, to be removed in 1.20", deprecated[0].Description)
+	})
+
+	t.Run("requests from the API Server should be reported", func(t *testing.T) {
+		data, err := os.ReadFile(metricsFile)
+		require.NoError(t, err)
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/metrics" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Write(data) //nolint: errcheck
+		}))
+		defer ts.Close()
+
+		client := discovery.NewDiscoveryClientForConfigOrDie(&rest.Config{Host: ts.URL}).RESTClient()
+		input, err := NewMetricsInputFromCluster(context.Background(), client, storer)
+		require.NoError(t, err)
+
+		_, deleted, err := input.GetDeprecations(context.Background())
+		require.NoError(t, err)
+		require.Len(t, deleted, 3)
+		require.Equal(t, APIServerLocation, deleted[0].Items[0].Location)
+	})
+}
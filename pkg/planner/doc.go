@@ -0,0 +1,5 @@
+// Package planner provide types and methods used to plan an upgrade across
+// many Kubernetes minor versions, telling on which hop each API breaks
+package planner
+
+// import "github.com/kubepug/kubepug/pkg/planner"
@@ -0,0 +1,237 @@
+package planner
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/Masterminds/semver/v3"
+
+	apis "github.com/kubepug/kubepug/pkg/apis/v1alpha1"
+	"github.com/kubepug/kubepug/pkg/results"
+	"github.com/kubepug/kubepug/pkg/store/generatedstore"
+)
+
+// unknownVersion is used as the deprecation or removal version of the APIs found without one
+const unknownVersion = "unknown"
+
+// Plan is an upgrade from the Current to the Target Kubernetes version, going through
+// every minor version between them
+type Plan struct {
+	Current string `json:"current" yaml:"current"`
+	Target  string `json:"target" yaml:"target"`
+	// Hops contains the APIs that become deprecated or removed on each version. The first
+	// hop is the current version, with the APIs already deprecated or removed
+	Hops []Hop `json:"hops" yaml:"hops"`
+	// Checklist contains the migrations to be done, ordered by the version they must be done
+	Checklist []Migration `json:"checklist" yaml:"checklist"`
+}
+
+// Hop is one of the versions of the upgrade
+type Hop struct {
+	Version    string               `json:"version" yaml:"version"`
+	Deprecated []results.ResultItem `json:"deprecated,omitempty" yaml:"deprecated,omitempty"`
+	Removed    []results.ResultItem `json:"removed,omitempty" yaml:"removed,omitempty"`
+}
+
+// Migration is an API in use that is deprecated or removed up to the target version
+type Migration struct {
+	Group       string                 `json:"group,omitempty" yaml:"group,omitempty"`
+	Version     string                 `json:"version" yaml:"version"`
+	Kind        string                 `json:"kind" yaml:"kind"`
+	Replacement *apis.GroupVersionKind `json:"replacement,omitempty" yaml:"replacement,omitempty"`
+	// ReplacementAvailable defines if the replacement can already be used on the current version
+	ReplacementAvailable *bool `json:"replacement_available,omitempty" yaml:"replacement_available,omitempty"`
+	// DeprecatedIn and RemovedIn are the versions the API was (or will be) deprecated and removed
+	DeprecatedIn string `json:"deprecated_in,omitempty" yaml:"deprecated_in,omitempty"`
+	RemovedIn    string `json:"removed_in,omitempty" yaml:"removed_in,omitempty"`
+	// MigrateBy is the latest hop the migration can be deferred to, as it must be done before
+	// upgrading to the next one. It is empty when the API is not removed up to the target version
+	MigrateBy string         `json:"migrate_by,omitempty" yaml:"migrate_by,omitempty"`
+	Items     []results.Item `json:"items,omitempty" yaml:"items,omitempty"`
+}
+
+// NewPlan compares the APIs found with the store on each minor version from current to target. The found
+// result must have been generated comparing the APIs with the target version, so it contains all the
+// APIs that are deprecated or removed up to it. APIs not known by the store, like the versions of
+// CustomResourceDefinitions, have no version history, so they keep the status they were found with
+// and are reported on the current version
+func NewPlan(ctx context.Context, storer *generatedstore.GeneratedStore, current, target string, found *results.Result) (*Plan, error) {
+	versions, err := Hops(current, target)
+	if err != nil {
+		return nil, err
+	}
+
+	apisInUse := make([]results.ResultItem, 0)
+	for _, item := range results.MergeResultItems(found.DeprecatedAPIs, found.DeletedAPIs) {
+		// The plan is made of API migrations, deprecated fields are not part of it
+		if item.Field == "" {
+			apisInUse = append(apisInUse, item)
+		}
+	}
+
+	// unversioned contains the status found for the APIs not known by the store, which would
+	// otherwise never be deprecated or removed on any hop
+	unversioned := make(map[int]apis.APIVersionStatus)
+	for i := range apisInUse {
+		status, err := storer.GetAPIDefinition(ctx, apisInUse[i].Group, apisInUse[i].Version, apisInUse[i].Kind)
+		if err != nil {
+			return nil, err
+		}
+		if status == (apis.APIVersionStatus{}) {
+			unversioned[i] = foundStatus(&apisInUse[i], found.DeletedAPIs)
+		}
+	}
+	plan := &Plan{
+		Current:   versions[0],
+		Target:    versions[len(versions)-1],
+		Checklist: make([]Migration, 0),
+	}
+
+	// previous contains the status of each API on the previous hop, so only what changes on a hop is reported
+	previous := make([]apis.APIVersionStatus, len(apisInUse))
+	migrations := make([]*Migration, len(apisInUse))
+	for _, version := range versions {
+		hopStore, err := storer.WithMinVersion(version)
+		if err != nil {
+			return nil, err
+		}
+
+		hop := Hop{Version: version}
+		for i := range apisInUse {
+			status, ok := unversioned[i]
+			if !ok {
+				status, err = hopStore.GetAPIDefinition(ctx, apisInUse[i].Group, apisInUse[i].Version, apisInUse[i].Kind)
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			item := apisInUse[i]
+			item.Replacement = status.Replacement
+			item.ReplacementAvailable = status.ReplacementAvailable
+			switch {
+			case status.DeletedVersion != "" && previous[i].DeletedVersion == "":
+				item.K8sVersion = status.DeletedVersion
+				hop.Removed = append(hop.Removed, item)
+			case status.DeprecationVersion != "" && previous[i].DeprecationVersion == "":
+				item.K8sVersion = status.DeprecationVersion
+				hop.Deprecated = append(hop.Deprecated, item)
+			}
+			previous[i] = status
+
+			if status.DeprecationVersion == "" && status.DeletedVersion == "" {
+				continue
+			}
+			if migrations[i] == nil {
+				migrations[i] = &Migration{
+					Group:   item.Group,
+					Version: item.Version,
+					Kind:    item.Kind,
+					Items:   item.Items,
+				}
+			}
+			migrations[i].Replacement = status.Replacement
+			migrations[i].ReplacementAvailable = status.ReplacementAvailable
+			migrations[i].DeprecatedIn = status.DeprecationVersion
+			if migrations[i].RemovedIn == "" && status.DeletedVersion != "" {
+				migrations[i].RemovedIn = status.DeletedVersion
+				migrations[i].MigrateBy = migrateBy(versions, version)
+			}
+		}
+		plan.Hops = append(plan.Hops, hop)
+	}
+
+	for _, migration := range migrations {
+		if migration != nil {
+			plan.Checklist = append(plan.Checklist, *migration)
+		}
+	}
+	sortChecklist(plan.Checklist, versions)
+	return plan, nil
+}
+
+// foundStatus returns the status an API was found with, which is deleted when it is on the deleted APIs
+func foundStatus(item *results.ResultItem, deleted []results.ResultItem) apis.APIVersionStatus {
+	status := apis.APIVersionStatus{
+		Description:          item.Description,
+		Replacement:          item.Replacement,
+		ReplacementAvailable: item.ReplacementAvailable,
+	}
+	version := item.K8sVersion
+	if version == "" {
+		version = unknownVersion
+	}
+	for i := range deleted {
+		if deleted[i].Group == item.Group && deleted[i].Version == item.Version && deleted[i].Kind == item.Kind && deleted[i].Field == "" {
+			status.DeletedVersion = version
+			return status
+		}
+	}
+	status.DeprecationVersion = version
+	return status
+}
+
+// Hops returns every minor version from current to target, including both. The versions
+// are returned on the same format used by the store, like 1.26
+func Hops(current, target string) ([]string, error) {
+	from, err := semver.NewVersion(current)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse current version %s: %w", current, err)
+	}
+	to, err := semver.NewVersion(target)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse target version %s: %w", target, err)
+	}
+	if from.Major() != to.Major() {
+		return nil, fmt.Errorf("upgrades between major versions are not supported")
+	}
+	if to.Minor() <= from.Minor() {
+		return nil, fmt.Errorf("target version %s should be newer than the current version %s", target, current)
+	}
+
+	versions := make([]string, 0, to.Minor()-from.Minor()+1)
+	for minor := from.Minor(); minor <= to.Minor(); minor++ {
+		versions = append(versions, fmt.Sprintf("%d.%d", from.Major(), minor))
+	}
+	return versions, nil
+}
+
+// migrateBy returns the hop before the one removing the API. APIs already removed on
+// the current version must be migrated right away
+func migrateBy(versions []string, removedOn string) string {
+	for i := range versions {
+		if versions[i] == removedOn && i > 0 {
+			return versions[i-1]
+		}
+	}
+	return versions[0]
+}
+
+// sortChecklist orders the migrations by the hop they must be done, leaving the ones that
+// can be deferred beyond the target version to the end
+func sortChecklist(checklist []Migration, versions []string) {
+	position := make(map[string]int, len(versions))
+	for i := range versions {
+		position[versions[i]] = i
+	}
+	order := func(m *Migration) int {
+		if m.MigrateBy == "" {
+			return len(versions)
+		}
+		return position[m.MigrateBy]
+	}
+
+	sort.SliceStable(checklist, func(i, j int) bool {
+		if order(&checklist[i]) != order(&checklist[j]) {
+			return order(&checklist[i]) < order(&checklist[j])
+		}
+		if checklist[i].Group != checklist[j].Group {
+			return checklist[i].Group < checklist[j].Group
+		}
+		if checklist[i].Kind != checklist[j].Kind {
+			return checklist[i].Kind < checklist[j].Kind
+		}
+		return checklist[i].Version < checklist[j].Version
+	})
+}
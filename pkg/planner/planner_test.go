@@ -0,0 +1,155 @@
+package planner
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	apis "github.com/kubepug/kubepug/pkg/apis/v1alpha1"
+	"github.com/kubepug/kubepug/pkg/results"
+	"github.com/kubepug/kubepug/pkg/store/generatedstore"
+)
+
+const planData = "../../test/testdata/plan/data.json"
+
+func TestHops(t *testing.T) {
+	tests := []struct {
+		name    string
+		current string
+		target  string
+		want    []string
+		wantErr string
+	}{
+		{
+			name:    "every minor version should be a hop",
+			current: "v1.25.3",
+			target:  "v1.28.0",
+			want:    []string{"1.25", "1.26", "1.27", "1.28"},
+		},
+		{
+			name:    "versions without the v prefix should be accepted",
+			current: "1.28",
+			target:  "1.29",
+			want:    []string{"1.28", "1.29"},
+		},
+		{
+			name:    "target should be newer than current",
+			current: "v1.28.0",
+			target:  "v1.28.5",
+			wantErr: "should be newer than the current version",
+		},
+		{
+			name:    "major upgrades are not supported",
+			current: "v1.28.0",
+			target:  "v2.0.0",
+			wantErr: "upgrades between major versions are not supported",
+		},
+		{
+			name:    "invalid versions should fail",
+			current: "xpto",
+			target:  "v1.28.0",
+			wantErr: "failed to parse current version",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Hops(tt.current, tt.target)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNewPlan(t *testing.T) {
+	storer, err := generatedstore.NewGeneratedStore(context.Background(), generatedstore.StoreConfig{Path: planData, MinVersion: "v1.29.0"})
+	require.NoError(t, err)
+
+	cronjobs := []results.Item{{Scope: "OBJECT", ObjectName: "backup", Namespace: "apps"}}
+	hpas := []results.Item{{Scope: "OBJECT", ObjectName: "web", Namespace: "apps"}}
+	found := &results.Result{
+		DeprecatedAPIs: []results.ResultItem{
+			{Group: "resource.k8s.io", Version: "v1alpha2", Kind: "ResourceClass", K8sVersion: "1.29"},
+		},
+		DeletedAPIs: []results.ResultItem{
+			{Group: "autoscaling", Version: "v2beta2", Kind: "HorizontalPodAutoscaler", K8sVersion: "1.26", Items: hpas},
+			{Group: "batch", Version: "v1beta1", Kind: "CronJob", K8sVersion: "1.25", Items: cronjobs},
+		},
+	}
+
+	plan, err := NewPlan(context.Background(), storer, "v1.24.0", "v1.29.0", found)
+	require.NoError(t, err)
+	require.Equal(t, "1.24", plan.Current)
+	require.Equal(t, "1.29", plan.Target)
+
+	hopAPIs := func(items []results.ResultItem) []string {
+		names := make([]string, 0, len(items))
+		for i := range items {
+			names = append(names, items[i].Kind+"@"+items[i].K8sVersion)
+		}
+		return names
+	}
+	require.Len(t, plan.Hops, 6)
+	require.Equal(t, []string{"HorizontalPodAutoscaler@1.23", "CronJob@1.21"}, hopAPIs(plan.Hops[0].Deprecated))
+	require.Empty(t, plan.Hops[0].Removed)
+	require.Equal(t, []string{"CronJob@1.25"}, hopAPIs(plan.Hops[1].Removed))
+	require.Equal(t, []string{"HorizontalPodAutoscaler@1.26"}, hopAPIs(plan.Hops[2].Removed))
+	require.Empty(t, hopAPIs(plan.Hops[3].Removed))
+	require.Equal(t, []string{"ResourceClass@1.29"}, hopAPIs(plan.Hops[5].Deprecated))
+
+	require.Equal(t, []Migration{
+		{
+			Group: "batch", Version: "v1beta1", Kind: "CronJob",
+			Replacement:  &apis.GroupVersionKind{Group: "batch", Version: "v1", Kind: "CronJob"},
+			DeprecatedIn: "1.21", RemovedIn: "1.25", MigrateBy: "1.24", Items: cronjobs,
+		},
+		{
+			Group: "autoscaling", Version: "v2beta2", Kind: "HorizontalPodAutoscaler",
+			Replacement:  &apis.GroupVersionKind{Group: "autoscaling", Version: "v2", Kind: "HorizontalPodAutoscaler"},
+			DeprecatedIn: "1.23", RemovedIn: "1.26", MigrateBy: "1.25", Items: hpas,
+		},
+		{
+			Group: "resource.k8s.io", Version: "v1alpha2", Kind: "ResourceClass",
+			DeprecatedIn: "1.29", Items: []results.Item{},
+		},
+	}, plan.Checklist)
+}
+
+func TestNewPlanUnversioned(t *testing.T) {
+	storer, err := generatedstore.NewGeneratedStore(context.Background(), generatedstore.StoreConfig{Path: planData, MinVersion: "v1.27.0"})
+	require.NoError(t, err)
+
+	widgets := []results.Item{{Scope: "OBJECT", ObjectName: "legacy-widget", Namespace: "apps"}}
+	requests := []results.Item{results.RequestedItem("flowschemas", "apiserver:/metrics")}
+	found := &results.Result{
+		DeprecatedAPIs: []results.ResultItem{
+			{Group: "example.com", Version: "v1beta1", Kind: "Widget", K8sVersion: "unknown", Items: widgets},
+		},
+		DeletedAPIs: []results.ResultItem{
+			{Group: "flowcontrol.apiserver.k8s.io", Version: "v1alpha1", K8sVersion: "1.26", Items: requests},
+		},
+	}
+
+	plan, err := NewPlan(context.Background(), storer, "v1.25.0", "v1.27.0", found)
+	require.NoError(t, err)
+	require.Len(t, plan.Hops, 3)
+	require.Equal(t, []results.ResultItem{found.DeprecatedAPIs[0]}, plan.Hops[0].Deprecated)
+	require.Equal(t, []results.ResultItem{found.DeletedAPIs[0]}, plan.Hops[0].Removed)
+	require.Empty(t, plan.Hops[1].Deprecated)
+	require.Empty(t, plan.Hops[1].Removed)
+
+	require.Equal(t, []Migration{
+		{
+			Group: "flowcontrol.apiserver.k8s.io", Version: "v1alpha1",
+			RemovedIn: "1.26", MigrateBy: "1.25", Items: requests,
+		},
+		{
+			Group: "example.com", Version: "v1beta1", Kind: "Widget",
+			DeprecatedIn: "unknown", Items: widgets,
+		},
+	}, plan.Checklist)
+}
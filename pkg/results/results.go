@@ -1,6 +1,8 @@
 package results
 
 import (
+	"fmt"
+
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
@@ -8,18 +10,40 @@ import (
 // convert to deprecatedItem to be used later in the results
 func ListObjects(items []unstructured.Unstructured) (deprecatedItems []Item) {
 	for _, d := range items {
-		name := d.GetName()
-		namespace := d.GetNamespace()
-		if namespace != "" {
-			deprecatedItems = append(deprecatedItems, Item{Scope: namespacedObject, ObjectName: name, Namespace: namespace})
-		} else {
-			deprecatedItems = append(deprecatedItems, Item{Scope: clusterObject, ObjectName: name})
-		}
+		deprecatedItems = append(deprecatedItems, ObjectItem(d.GetName(), d.GetNamespace()))
 	}
 
 	return deprecatedItems
 }
 
+// ObjectItem returns the Item of a Kubernetes object, scoped by its namespace
+func ObjectItem(name, namespace string) Item {
+	if namespace != "" {
+		return Item{Scope: namespacedObject, ObjectName: name, Namespace: namespace}
+	}
+	return Item{Scope: clusterObject, ObjectName: name}
+}
+
+// CallerItem returns the Item of a client that called an API
+func CallerItem(user, userAgent string, calls int) Item {
+	return Item{Scope: apiCaller, User: user, UserAgent: userAgent, Calls: calls}
+}
+
+// RequestedItem returns the Item of an API resource that was requested, as
+// recorded by the API Server metrics found on location
+func RequestedItem(resource, location string) Item {
+	return Item{Scope: apiRequest, ObjectName: resource, Location: location}
+}
+
+// FileLocation returns the Location of the item followed by the line and column it was found
+// on, when known, like path:line:col, so editors and CI tools can jump straight to it
+func (i Item) FileLocation() string {
+	if i.Line == 0 {
+		return i.Location
+	}
+	return fmt.Sprintf("%s:%d:%d", i.Location, i.Line, i.Column)
+}
+
 func CreateItem(group, version, kind string, items []Item) ResultItem {
 	return ResultItem{
 		Group:   group,
@@ -28,3 +52,85 @@ func CreateItem(group, version, kind string, items []Item) ResultItem {
 		Items:   items,
 	}
 }
+
+// MergeResultItems groups the ResultItems that refer to the same Group/Version/Kind and field, so
+// items found by different sources are reported together. The availability of the replacement
+// depends on the version of each cluster, so it becomes unknown when the merged ones disagree
+func MergeResultItems(resultItems ...[]ResultItem) (merged []ResultItem) {
+	index := make(map[string]int)
+	for _, items := range resultItems {
+		for i := range items {
+			key := items[i].Group + "/" + items[i].Version + "/" + items[i].Kind + "/" + items[i].Field
+			if pos, ok := index[key]; ok {
+				merged[pos].Items = append(merged[pos].Items, items[i].Items...)
+				if !sameAvailability(merged[pos].ReplacementAvailable, items[i].ReplacementAvailable) {
+					merged[pos].ReplacementAvailable = nil
+				}
+				continue
+			}
+			index[key] = len(merged)
+			item := items[i]
+			item.Items = append([]Item{}, items[i].Items...)
+			merged = append(merged, item)
+		}
+	}
+	return merged
+}
+
+func sameAvailability(a, b *bool) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// SetCluster records on every item of the result the cluster where it was found
+func SetCluster(result *Result, cluster string) {
+	for _, apis := range [][]ResultItem{result.DeprecatedAPIs, result.DeletedAPIs} {
+		for i := range apis {
+			for j := range apis[i].Items {
+				apis[i].Items[j].Cluster = cluster
+			}
+		}
+	}
+	for i := range result.StoredVersions {
+		result.StoredVersions[i].Cluster = cluster
+	}
+}
+
+// NewClusterSummary counts the APIs found on the result of a cluster
+func NewClusterSummary(cluster string, result *Result) ClusterSummary {
+	return ClusterSummary{
+		Name:           cluster,
+		DeprecatedAPIs: len(result.DeprecatedAPIs),
+		DeletedAPIs:    len(result.DeletedAPIs),
+		StoredVersions: len(result.StoredVersions),
+	}
+}
+
+// FailedClusters returns the names of the clusters that could not be scanned
+func FailedClusters(result *Result) []string {
+	failed := make([]string, 0)
+	for i := range result.Clusters {
+		if result.Clusters[i].Error != "" {
+			failed = append(failed, result.Clusters[i].Name)
+		}
+	}
+	return failed
+}
+
+// MergeResults merges the results of different scans, like the ones of different clusters, into
+// a single Result where the findings of the same API are reported together
+func MergeResults(scanResults ...Result) (merged Result) {
+	allDeprecated := make([][]ResultItem, 0, len(scanResults))
+	allDeleted := make([][]ResultItem, 0, len(scanResults))
+	for i := range scanResults {
+		allDeprecated = append(allDeprecated, scanResults[i].DeprecatedAPIs)
+		allDeleted = append(allDeleted, scanResults[i].DeletedAPIs)
+		merged.StoredVersions = append(merged.StoredVersions, scanResults[i].StoredVersions...)
+		merged.Clusters = append(merged.Clusters, scanResults[i].Clusters...)
+	}
+	merged.DeprecatedAPIs = MergeResultItems(allDeprecated...)
+	merged.DeletedAPIs = MergeResultItems(allDeleted...)
+	return merged
+}
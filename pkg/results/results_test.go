@@ -109,3 +109,119 @@ func TestListObjects(t *testing.T) {
 		})
 	}
 }
+
+func TestMergeResultItems(t *testing.T) {
+	live := []ResultItem{
+		{Group: "extensions", Version: "v1beta1", Kind: "Ingress", Items: []Item{{ObjectName: "live"}}},
+		{Group: "policy", Version: "v1beta1", Kind: "PodSecurityPolicy", Items: []Item{{ObjectName: "psp"}}},
+	}
+	helm := []ResultItem{
+		{Group: "extensions", Version: "v1beta1", Kind: "Ingress", Items: []Item{{ObjectName: "fromhelm", Location: "helm:default/app@1"}}},
+		{Group: "batch", Version: "v1beta1", Kind: "CronJob", Items: []Item{{ObjectName: "cron"}}},
+	}
+
+	want := []ResultItem{
+		{Group: "extensions", Version: "v1beta1", Kind: "Ingress", Items: []Item{{ObjectName: "live"}, {ObjectName: "fromhelm", Location: "helm:default/app@1"}}},
+		{Group: "policy", Version: "v1beta1", Kind: "PodSecurityPolicy", Items: []Item{{ObjectName: "psp"}}},
+		{Group: "batch", Version: "v1beta1", Kind: "CronJob", Items: []Item{{ObjectName: "cron"}}},
+	}
+
+	if got := MergeResultItems(live, helm); !reflect.DeepEqual(got, want) {
+		t.Errorf("MergeResultItems() = %v, want %v", got, want)
+	}
+	// Merging should not change the original slices
+	if len(live[0].Items) != 1 {
+		t.Errorf("MergeResultItems() changed the original items: %v", live[0].Items)
+	}
+	if got := MergeResultItems(); got != nil {
+		t.Errorf("MergeResultItems() = %v, want nil", got)
+	}
+
+	t.Run("replacement availability should be unknown when the merged items disagree", func(t *testing.T) {
+		available, unavailable := true, false
+		newItems := func(replacementAvailable *bool) []ResultItem {
+			return []ResultItem{{Group: "extensions", Version: "v1beta1", Kind: "Ingress", ReplacementAvailable: replacementAvailable}}
+		}
+
+		if got := MergeResultItems(newItems(&available), newItems(&available)); got[0].ReplacementAvailable == nil || !*got[0].ReplacementAvailable {
+			t.Errorf("MergeResultItems() availability = %v, want true", got[0].ReplacementAvailable)
+		}
+		if got := MergeResultItems(newItems(&unavailable), newItems(&available), newItems(&unavailable)); got[0].ReplacementAvailable != nil {
+			t.Errorf("MergeResultItems() availability = %v, want nil", *got[0].ReplacementAvailable)
+		}
+		if got := MergeResultItems(newItems(nil), newItems(&available)); got[0].ReplacementAvailable != nil {
+			t.Errorf("MergeResultItems() availability = %v, want nil", *got[0].ReplacementAvailable)
+		}
+	})
+}
+
+func TestMergeResults(t *testing.T) {
+	prod := Result{
+		DeletedAPIs:    []ResultItem{{Group: "extensions", Version: "v1beta1", Kind: "Ingress", Items: []Item{{ObjectName: "web"}}}},
+		StoredVersions: []StoredVersionItem{{CRD: "widgets.example.com", Version: "v1beta1"}},
+	}
+	SetCluster(&prod, "prod")
+	prod.Clusters = []ClusterSummary{NewClusterSummary("prod", &prod)}
+
+	staging := Result{
+		DeletedAPIs: []ResultItem{{Group: "extensions", Version: "v1beta1", Kind: "Ingress", Items: []Item{{ObjectName: "web"}}}},
+	}
+	SetCluster(&staging, "staging")
+	staging.Clusters = []ClusterSummary{NewClusterSummary("staging", &staging)}
+
+	want := Result{
+		DeletedAPIs: []ResultItem{
+			{Group: "extensions", Version: "v1beta1", Kind: "Ingress", Items: []Item{{ObjectName: "web", Cluster: "prod"}, {ObjectName: "web", Cluster: "staging"}}},
+		},
+		StoredVersions: []StoredVersionItem{{CRD: "widgets.example.com", Version: "v1beta1", Cluster: "prod"}},
+		Clusters: []ClusterSummary{
+			{Name: "prod", DeletedAPIs: 1, StoredVersions: 1},
+			{Name: "staging", DeletedAPIs: 1},
+		},
+	}
+
+	if got := MergeResults(prod, staging); !reflect.DeepEqual(got, want) {
+		t.Errorf("MergeResults() = %v, want %v", got, want)
+	}
+}
+
+func TestFailedClusters(t *testing.T) {
+	result := &Result{Clusters: []ClusterSummary{
+		{Name: "prod", DeletedAPIs: 1},
+		{Name: "staging", Error: "connection refused"},
+		{Name: "dev", Error: "context deadline exceeded"},
+	}}
+
+	if got, want := FailedClusters(result), []string{"staging", "dev"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("FailedClusters() = %v, want %v", got, want)
+	}
+	if got := FailedClusters(&Result{}); len(got) != 0 {
+		t.Errorf("FailedClusters() = %v, want no clusters", got)
+	}
+}
+
+func TestFileLocation(t *testing.T) {
+	tests := []struct {
+		name string
+		item Item
+		want string
+	}{
+		{
+			name: "items with a line should have the line and column",
+			item: Item{Location: "manifests/app.yaml", Document: 2, Line: 12, Column: 1},
+			want: "manifests/app.yaml:12:1",
+		},
+		{
+			name: "items without a line should have just the location",
+			item: Item{Location: "helm:default/app@1"},
+			want: "helm:default/app@1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.item.FileLocation(); got != tt.want {
+				t.Errorf("FileLocation() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -5,6 +5,8 @@ import "github.com/kubepug/kubepug/pkg/apis/v1alpha1"
 const (
 	namespacedObject = "OBJECT"
 	clusterObject    = "GLOBAL"
+	apiCaller        = "CALLER"
+	apiRequest       = "REQUESTED"
 )
 
 // Item definition of the Items inside a deprecated API
@@ -13,6 +15,26 @@ type Item struct {
 	ObjectName string `json:"objectname,omitempty" yaml:"objectname,omitempty"`
 	Namespace  string `json:"namespace,omitempty" yaml:"namespace,omitempty"`
 	Location   string `json:"location,omitempty" yaml:"location,omitempty"`
+	// FieldManager is the manager (eg.: a controller or kubectl) that wrote the object
+	// using the deprecated API, as recorded on the object managedFields
+	FieldManager string `json:"fieldmanager,omitempty" yaml:"fieldmanager,omitempty"`
+	// User, UserAgent and Calls identify a client that called the deprecated API, and how
+	// many times it did it, as recorded on the audit logs
+	User      string `json:"user,omitempty" yaml:"user,omitempty"`
+	UserAgent string `json:"useragent,omitempty" yaml:"useragent,omitempty"`
+	Calls     int    `json:"calls,omitempty" yaml:"calls,omitempty"`
+	// Rule identifies the rule (eg.: a webhook or RBAC rule index) that references the deprecated API
+	Rule string `json:"rule,omitempty" yaml:"rule,omitempty"`
+	// Cluster is the kubeconfig context the item was found on, when more than one cluster is scanned
+	Cluster string `json:"cluster,omitempty" yaml:"cluster,omitempty"`
+	// Document, Line and Column locate the object inside the file on Location. Document is the
+	// position of the object document on the file, and Line and Column are where its apiVersion is
+	Document int `json:"document,omitempty" yaml:"document,omitempty"`
+	Line     int `json:"line,omitempty" yaml:"line,omitempty"`
+	Column   int `json:"column,omitempty" yaml:"column,omitempty"`
+	// File is true when Location is a file on disk. Other locations, like Helm releases, chart templates
+	// or API server endpoints, only identify where the item was found
+	File bool `json:"-" yaml:"-"`
 }
 
 type ResultItem struct {
@@ -20,14 +42,45 @@ type ResultItem struct {
 	Kind        string                     `json:"kind,omitempty" yaml:"kind,omitempty"`
 	Version     string                     `json:"version,omitempty" yaml:"version,omitempty"`
 	Replacement *v1alpha1.GroupVersionKind `json:"replacement,omitempty" yaml:"replacement,omitempty"`
+	// ReplacementAvailable defines if the replacement can already be used on the Kubernetes version
+	// currently running, so the API can be migrated before upgrading. It is nil when this is unknown
+	ReplacementAvailable *bool `json:"replacement_available,omitempty" yaml:"replacement_available,omitempty"`
+	// Field is the path of a deprecated field of the API, like spec.template.spec.serviceAccount.
+	// It is empty when the whole API is deprecated
+	Field string `json:"field,omitempty" yaml:"field,omitempty"`
 	// K8sVersion defines which k8s version this API was flagged
 	K8sVersion  string `json:"k8sversion,omitempty" yaml:"k8sversion,omitempty"`
 	Description string `json:"description,omitempty" yaml:"description,omitempty"`
 	Items       []Item `json:"deleted_items,omitempty" yaml:"deleted_items,omitempty"`
 }
 
+// StoredVersionItem is a CustomResourceDefinition that still has objects stored on a version
+// that is deprecated, not served or not declared anymore. The objects must be migrated to the
+// storage version, and the version pruned from status.storedVersions, before the version is removed
+type StoredVersionItem struct {
+	CRD            string `json:"crd" yaml:"crd"`
+	Group          string `json:"group,omitempty" yaml:"group,omitempty"`
+	Kind           string `json:"kind,omitempty" yaml:"kind,omitempty"`
+	Version        string `json:"version" yaml:"version"`
+	StorageVersion string `json:"storageversion,omitempty" yaml:"storageversion,omitempty"`
+	Reason         string `json:"reason" yaml:"reason"`
+	Cluster        string `json:"cluster,omitempty" yaml:"cluster,omitempty"`
+}
+
+// ClusterSummary counts the findings of one of the clusters scanned on the same run
+type ClusterSummary struct {
+	Name           string `json:"name" yaml:"name"`
+	DeprecatedAPIs int    `json:"deprecated_apis" yaml:"deprecated_apis"`
+	DeletedAPIs    int    `json:"deleted_apis" yaml:"deleted_apis"`
+	StoredVersions int    `json:"stored_versions,omitempty" yaml:"stored_versions,omitempty"`
+	// Error is set when the cluster could not be scanned
+	Error string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
 // Result to show final user
 type Result struct {
-	DeprecatedAPIs []ResultItem `json:"deprecated_apis" yaml:"deprecated_apis"`
-	DeletedAPIs    []ResultItem `json:"deleted_apis" yaml:"deleted_apis"`
+	DeprecatedAPIs []ResultItem        `json:"deprecated_apis" yaml:"deprecated_apis"`
+	DeletedAPIs    []ResultItem        `json:"deleted_apis" yaml:"deleted_apis"`
+	StoredVersions []StoredVersionItem `json:"stored_versions,omitempty" yaml:"stored_versions,omitempty"`
+	Clusters       []ClusterSummary    `json:"clusters,omitempty" yaml:"clusters,omitempty"`
 }
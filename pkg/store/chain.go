@@ -0,0 +1,128 @@
+package store
+
+import (
+	"context"
+	"sort"
+
+	api "github.com/kubepug/kubepug/pkg/apis/v1alpha1"
+	"github.com/kubepug/kubepug/pkg/errors"
+)
+
+// ChainStore queries a list of stores, returning the result of the first one that knows the API
+type ChainStore struct {
+	stores []DefinitionStorer
+}
+
+// NewChainStore returns a ChainStore querying the stores in the order they are passed
+func NewChainStore(stores ...DefinitionStorer) *ChainStore {
+	return &ChainStore{stores: stores}
+}
+
+// GetAPIDefinition returns the status of the API on the first store that knows it, so the first
+// stores win over the later ones
+func (c *ChainStore) GetAPIDefinition(ctx context.Context, group, version, kind string) (api.APIVersionStatus, error) {
+	for _, s := range c.stores {
+		result, err := s.GetAPIDefinition(ctx, group, version, kind)
+		if err != nil {
+			if errors.IsErrAPINotFound(err) {
+				continue
+			}
+			return api.APIVersionStatus{}, err
+		}
+		if result != (api.APIVersionStatus{}) {
+			return result, nil
+		}
+	}
+	return api.APIVersionStatus{}, nil
+}
+
+// GetKindForResource returns the Kind of the resource on the first KindResolver store that knows it
+func (c *ChainStore) GetKindForResource(ctx context.Context, group, version, resource string) (string, error) {
+	for _, s := range c.stores {
+		resolver, ok := s.(KindResolver)
+		if !ok {
+			continue
+		}
+		kind, err := resolver.GetKindForResource(ctx, group, version, resource)
+		if err != nil {
+			if errors.IsErrAPINotFound(err) {
+				continue
+			}
+			return "", err
+		}
+		return kind, nil
+	}
+	return "", errors.ErrAPINotFound
+}
+
+// ListAPIs returns the APIs of a group known by any of the APILister stores, without duplicates
+func (c *ChainStore) ListAPIs(ctx context.Context, group string) ([]api.GroupVersionKind, error) {
+	found := make(map[api.GroupVersionKind]struct{})
+	apis := make([]api.GroupVersionKind, 0)
+	for _, s := range c.stores {
+		lister, ok := s.(APILister)
+		if !ok {
+			continue
+		}
+		list, err := lister.ListAPIs(ctx, group)
+		if err != nil {
+			return nil, err
+		}
+		for _, gvk := range list {
+			if _, ok := found[gvk]; ok {
+				continue
+			}
+			found[gvk] = struct{}{}
+			apis = append(apis, gvk)
+		}
+	}
+	return apis, nil
+}
+
+// GetDeprecatedFields returns the deprecated fields of the API on the first FieldLister store that knows any
+func (c *ChainStore) GetDeprecatedFields(ctx context.Context, group, version, kind string) ([]api.FieldStatus, error) {
+	for _, s := range c.stores {
+		lister, ok := s.(FieldLister)
+		if !ok {
+			continue
+		}
+		fields, err := lister.GetDeprecatedFields(ctx, group, version, kind)
+		if err != nil {
+			return nil, err
+		}
+		if len(fields) > 0 {
+			return fields, nil
+		}
+	}
+	return nil, nil
+}
+
+// IsReleased reports whether the release is already out on the first VersionComparer store, being
+// false when none of the stores compares versions
+func (c *ChainStore) IsReleased(release string) bool {
+	for _, s := range c.stores {
+		if comparer, ok := s.(VersionComparer); ok {
+			return comparer.IsReleased(release)
+		}
+	}
+	return false
+}
+
+// GetGroups returns the sorted API groups known by any of the GroupLister stores
+func (c *ChainStore) GetGroups() []string {
+	groups := make(map[string]struct{})
+	for _, s := range c.stores {
+		if lister, ok := s.(GroupLister); ok {
+			for _, group := range lister.GetGroups() {
+				groups[group] = struct{}{}
+			}
+		}
+	}
+
+	list := make([]string, 0, len(groups))
+	for group := range groups {
+		list = append(list, group)
+	}
+	sort.Strings(list)
+	return list
+}
@@ -0,0 +1,146 @@
+package store
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	api "github.com/kubepug/kubepug/pkg/apis/v1alpha1"
+	"github.com/kubepug/kubepug/pkg/errors"
+)
+
+type fakeStore struct {
+	apis      map[string]api.APIVersionStatus
+	resources map[string]string
+	fields    map[string][]api.FieldStatus
+	groups    []string
+}
+
+func (f *fakeStore) GetAPIDefinition(_ context.Context, group, version, kind string) (api.APIVersionStatus, error) {
+	status, ok := f.apis[group+"/"+version+"/"+kind]
+	if !ok {
+		return api.APIVersionStatus{}, errors.ErrAPINotFound
+	}
+	return status, nil
+}
+
+func (f *fakeStore) GetKindForResource(_ context.Context, group, version, resource string) (string, error) {
+	kind, ok := f.resources[group+"/"+version+"/"+resource]
+	if !ok {
+		return "", errors.ErrAPINotFound
+	}
+	return kind, nil
+}
+
+func (f *fakeStore) ListAPIs(_ context.Context, group string) ([]api.GroupVersionKind, error) {
+	list := make([]api.GroupVersionKind, 0)
+	for key := range f.apis {
+		gvk := strings.Split(key, "/")
+		if gvk[0] == group {
+			list = append(list, api.GroupVersionKind{Group: gvk[0], Version: gvk[1], Kind: gvk[2]})
+		}
+	}
+	return list, nil
+}
+
+func (f *fakeStore) GetDeprecatedFields(_ context.Context, group, version, kind string) ([]api.FieldStatus, error) {
+	return f.fields[group+"/"+version+"/"+kind], nil
+}
+
+func (f *fakeStore) GetGroups() []string {
+	return f.groups
+}
+
+// fakeComparer is a fakeStore where the releases are the ones up to 1.22
+type fakeComparer struct {
+	fakeStore
+}
+
+func (f *fakeComparer) IsReleased(release string) bool {
+	return release <= "1.22"
+}
+
+func TestChainStore(t *testing.T) {
+	first := &fakeStore{
+		apis: map[string]api.APIVersionStatus{
+			"extensions/v1beta1/Ingress": {DeletedVersion: "1.22"},
+			"example.com/v1/Widget":      {},
+		},
+		resources: map[string]string{"extensions/v1beta1/ingresses": "Ingress"},
+		groups:    []string{"extensions"},
+	}
+	second := &fakeStore{
+		apis: map[string]api.APIVersionStatus{
+			"extensions/v1beta1/Ingress": {DeletedVersion: "1.99"},
+			"example.com/v1/Widget":      {DeprecationVersion: "unknown"},
+		},
+		resources: map[string]string{"example.com/v1/widgets": "Widget"},
+		fields:    map[string][]api.FieldStatus{"apps/v1/Deployment": {{Path: []string{"spec", "legacy"}, DeprecationVersion: "1.20"}}},
+		groups:    []string{"example.com", "extensions"},
+	}
+	chain := NewChainStore(first, second)
+
+	t.Run("first store knowing the API should be used", func(t *testing.T) {
+		got, err := chain.GetAPIDefinition(context.Background(), "extensions", "v1beta1", "Ingress")
+		require.NoError(t, err)
+		require.Equal(t, "1.22", got.DeletedVersion)
+	})
+
+	t.Run("empty results should fallback to the next store", func(t *testing.T) {
+		got, err := chain.GetAPIDefinition(context.Background(), "example.com", "v1", "Widget")
+		require.NoError(t, err)
+		require.Equal(t, "unknown", got.DeprecationVersion)
+	})
+
+	t.Run("unknown API should return an empty result", func(t *testing.T) {
+		got, err := chain.GetAPIDefinition(context.Background(), "xpto.io", "v1", "Xpto")
+		require.NoError(t, err)
+		require.Equal(t, api.APIVersionStatus{}, got)
+	})
+
+	t.Run("kinds should be resolved by any store", func(t *testing.T) {
+		kind, err := chain.GetKindForResource(context.Background(), "example.com", "v1", "widgets")
+		require.NoError(t, err)
+		require.Equal(t, "Widget", kind)
+
+		_, err = chain.GetKindForResource(context.Background(), "xpto.io", "v1", "xptos")
+		require.ErrorIs(t, err, errors.ErrAPINotFound)
+	})
+
+	t.Run("APIs of all the stores should be listed once", func(t *testing.T) {
+		got, err := chain.ListAPIs(context.Background(), "example.com")
+		require.NoError(t, err)
+		require.Equal(t, []api.GroupVersionKind{{Group: "example.com", Version: "v1", Kind: "Widget"}}, got)
+	})
+
+	t.Run("fields should be returned by the first store knowing them", func(t *testing.T) {
+		got, err := chain.GetDeprecatedFields(context.Background(), "apps", "v1", "Deployment")
+		require.NoError(t, err)
+		require.Equal(t, []api.FieldStatus{{Path: []string{"spec", "legacy"}, DeprecationVersion: "1.20"}}, got)
+
+		got, err = chain.GetDeprecatedFields(context.Background(), "apps", "v1", "DaemonSet")
+		require.NoError(t, err)
+		require.Empty(t, got)
+	})
+
+	t.Run("releases should be compared by the first store comparing them", func(t *testing.T) {
+		require.False(t, chain.IsReleased("1.20"))
+
+		comparer := NewChainStore(first, &fakeComparer{})
+		require.True(t, comparer.IsReleased("1.20"))
+		require.False(t, comparer.IsReleased("1.25"))
+	})
+
+	t.Run("groups of all the stores should be returned", func(t *testing.T) {
+		require.Equal(t, []string{"example.com", "extensions"}, chain.GetGroups())
+	})
+}
+
+func TestIncludeGroups(t *testing.T) {
+	lister := &fakeStore{groups: []string{"example.com"}}
+	require.Equal(t, []string{".k8s.io", "example.com"}, IncludeGroups(lister, []string{".k8s.io"}))
+	require.Empty(t, IncludeGroups(lister, []string{}))
+	require.Equal(t, []string{".k8s.io"}, IncludeGroups(nil, []string{".k8s.io"}))
+}
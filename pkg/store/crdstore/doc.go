@@ -0,0 +1,5 @@
+// Package crdstore contains the store that uses the versions declared on
+// CustomResourceDefinitions, from a cluster or from manifests
+package crdstore
+
+// import "github.com/kubepug/kubepug/pkg/store/crdstore"
@@ -0,0 +1,277 @@
+package crdstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/dynamic"
+
+	apis "github.com/kubepug/kubepug/pkg/apis/v1alpha1"
+	kerrors "github.com/kubepug/kubepug/pkg/errors"
+)
+
+const (
+	crdKind  = "CustomResourceDefinition"
+	crdGroup = "apiextensions.k8s.io"
+	// unknownVersion is used as the deprecation and deletion version, as CRDs don't
+	// tell when a version was deprecated
+	unknownVersion = "unknown"
+)
+
+var crdgvr = schema.GroupVersionResource{
+	Group:    crdGroup,
+	Version:  "v1",
+	Resource: "customresourcedefinitions",
+}
+
+// Version contains the fields of a CRD version relevant to know if it is deprecated
+type Version struct {
+	Name               string
+	Served             bool
+	Storage            bool
+	Deprecated         bool
+	DeprecationWarning string
+}
+
+// CRDStore is a store populated with the versions of CustomResourceDefinitions
+type CRDStore struct {
+	db apis.APIGroups
+	// resources maps group/version/resource to the Kind served by the resource
+	resources map[string]string
+}
+
+// NewCRDStoreFromCluster returns a CRDStore populated with the CustomResourceDefinitions of a cluster
+func NewCRDStoreFromCluster(ctx context.Context, client dynamic.Interface) (*CRDStore, error) {
+	list, err := client.Resource(crdgvr).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the CustomResourceDefinitions: %w", err)
+	}
+	return NewCRDStore(list.Items), nil
+}
+
+// NewCRDStoreFromFiles returns a CRDStore populated with the CustomResourceDefinitions found on
+// a manifest file, or on all the manifest files inside a directory and its subdirectories
+func NewCRDStoreFromFiles(location string) (*CRDStore, error) {
+	if _, err := os.Stat(location); err != nil {
+		return nil, fmt.Errorf("failed to read CRDs location %s: %w", location, err)
+	}
+
+	crds := make([]unstructured.Unstructured, 0)
+	err := filepath.WalkDir(location, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		ext := strings.ToLower(filepath.Ext(path))
+		if path != location && ext != ".yaml" && ext != ".yml" && ext != ".json" {
+			return nil
+		}
+
+		fileCRDs, err := readCRDs(path)
+		if err != nil {
+			logrus.Warningf("failed to read CRDs from %s, skipping: %s", path, err)
+			return nil
+		}
+		crds = append(crds, fileCRDs...)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CRDs location %s: %w", location, err)
+	}
+
+	return NewCRDStore(crds), nil
+}
+
+func readCRDs(path string) ([]unstructured.Unstructured, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	crds := make([]unstructured.Unstructured, 0)
+	decoder := utilyaml.NewYAMLOrJSONDecoder(file, 4096)
+	for {
+		obj := make(map[string]interface{})
+		if err := decoder.Decode(&obj); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, err
+		}
+		u := unstructured.Unstructured{Object: obj}
+		if u.IsList() {
+			// Errors here mean the items are not objects, and can't be CRDs
+			_ = u.EachListItem(func(item runtime.Object) error {
+				if crd, ok := item.(*unstructured.Unstructured); ok && isCRD(crd) {
+					crds = append(crds, *crd)
+				}
+				return nil
+			})
+			continue
+		}
+		if isCRD(&u) {
+			crds = append(crds, u)
+		}
+	}
+	return crds, nil
+}
+
+func isCRD(obj *unstructured.Unstructured) bool {
+	return obj.GetKind() == crdKind && strings.HasPrefix(obj.GetAPIVersion(), crdGroup+"/")
+}
+
+// NewCRDStore returns a CRDStore populated with the versions of the CustomResourceDefinitions
+func NewCRDStore(crds []unstructured.Unstructured) *CRDStore {
+	s := &CRDStore{
+		db:        make(apis.APIGroups),
+		resources: make(map[string]string),
+	}
+
+	for i := range crds {
+		group, _, _ := unstructured.NestedString(crds[i].Object, "spec", "group")
+		kind, _, _ := unstructured.NestedString(crds[i].Object, "spec", "names", "kind")
+		plural, _, _ := unstructured.NestedString(crds[i].Object, "spec", "names", "plural")
+		if group == "" || kind == "" {
+			logrus.Warningf("CRD %s does not contain a group or a kind, skipping", crds[i].GetName())
+			continue
+		}
+
+		versions := GetVersions(&crds[i])
+		if _, ok := s.db[group]; !ok {
+			s.db[group] = make(apis.APIKinds)
+		}
+		s.db[group][kind] = make(apis.APIVersion)
+
+		replacement := getReplacement(versions)
+		for _, v := range versions {
+			s.resources[group+"/"+v.Name+"/"+plural] = kind
+
+			status := apis.APIVersionStatus{}
+			switch {
+			case !v.Served:
+				status.DeletedVersion = unknownVersion
+				status.Description = fmt.Sprintf("%s/%s %s is not served anymore", group, v.Name, kind)
+			case v.Deprecated:
+				status.DeprecationVersion = unknownVersion
+				status.Description = v.DeprecationWarning
+				if status.Description == "" {
+					// The same default warning returned by the API Server
+					status.Description = fmt.Sprintf("%s/%s %s is deprecated", group, v.Name, kind)
+				}
+			}
+
+			if (status.DeletedVersion != "" || status.DeprecationVersion != "") && replacement != "" && replacement != v.Name {
+				status.Replacement = &apis.GroupVersionKind{Group: group, Version: replacement, Kind: kind}
+			}
+			s.db[group][kind][v.Name] = status
+		}
+	}
+
+	return s
+}
+
+// GetVersions returns the versions of a CRD, supporting the apiextensions.k8s.io/v1beta1
+// CRDs that may have a single version on spec.version
+func GetVersions(crd *unstructured.Unstructured) []Version {
+	versions := make([]Version, 0)
+	list, _, _ := unstructured.NestedSlice(crd.Object, "spec", "versions")
+	for _, item := range list {
+		v, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		version := Version{}
+		version.Name, _, _ = unstructured.NestedString(v, "name")
+		version.Served, _, _ = unstructured.NestedBool(v, "served")
+		version.Storage, _, _ = unstructured.NestedBool(v, "storage")
+		version.Deprecated, _, _ = unstructured.NestedBool(v, "deprecated")
+		version.DeprecationWarning, _, _ = unstructured.NestedString(v, "deprecationWarning")
+		if version.Name != "" {
+			versions = append(versions, version)
+		}
+	}
+
+	if len(versions) == 0 {
+		if name, _, _ := unstructured.NestedString(crd.Object, "spec", "version"); name != "" {
+			versions = append(versions, Version{Name: name, Served: true, Storage: true})
+		}
+	}
+	return versions
+}
+
+// getReplacement returns the version that should be used instead of the deprecated ones,
+// being the storage version or, if it is deprecated as well, the first served one that is not
+func getReplacement(versions []Version) string {
+	for _, v := range versions {
+		if v.Storage && v.Served && !v.Deprecated {
+			return v.Name
+		}
+	}
+	for _, v := range versions {
+		if v.Served && !v.Deprecated {
+			return v.Name
+		}
+	}
+	return ""
+}
+
+// GetAPIDefinition returns the status of a CRD version, failing when the version is not known
+func (s *CRDStore) GetAPIDefinition(_ context.Context, group, version, kind string) (apis.APIVersionStatus, error) {
+	status, ok := s.db[group][kind][version]
+	if !ok {
+		return apis.APIVersionStatus{}, kerrors.ErrAPINotFound
+	}
+	return status, nil
+}
+
+// GetKindForResource finds the Kind served by a resource, using the CRD plural name
+func (s *CRDStore) GetKindForResource(_ context.Context, group, version, resource string) (string, error) {
+	kind, ok := s.resources[group+"/"+version+"/"+resource]
+	if !ok {
+		return "", kerrors.ErrAPINotFound
+	}
+	return kind, nil
+}
+
+// ListAPIs returns the Group/Version/Kinds of the CRDs of a group, sorted by Kind and Version
+func (s *CRDStore) ListAPIs(_ context.Context, group string) ([]apis.GroupVersionKind, error) {
+	list := make([]apis.GroupVersionKind, 0)
+	for kind, versions := range s.db[group] {
+		for version := range versions {
+			list = append(list, apis.GroupVersionKind{Group: group, Version: version, Kind: kind})
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Kind != list[j].Kind {
+			return list[i].Kind < list[j].Kind
+		}
+		return list[i].Version < list[j].Version
+	})
+	return list, nil
+}
+
+// GetGroups returns the API groups of the CRDs
+func (s *CRDStore) GetGroups() []string {
+	groups := make([]string, 0, len(s.db))
+	for group := range s.db {
+		groups = append(groups, group)
+	}
+	sort.Strings(groups)
+	return groups
+}
@@ -0,0 +1,158 @@
+package crdstore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	apis "github.com/kubepug/kubepug/pkg/apis/v1alpha1"
+	"github.com/kubepug/kubepug/pkg/errors"
+)
+
+const crdsDir = "../../../test/testdata/crds"
+
+func TestNewCRDStoreFromFiles(t *testing.T) {
+	t.Run("non existing location should fail", func(t *testing.T) {
+		_, err := NewCRDStoreFromFiles("/tmp/xpto/crds")
+		require.ErrorContains(t, err, "failed to read CRDs location")
+	})
+
+	t.Run("CRDs on nested directories should be read", func(t *testing.T) {
+		s, err := NewCRDStoreFromFiles(crdsDir)
+		require.NoError(t, err)
+		require.Equal(t, []string{"acme.io", "example.com"}, s.GetGroups())
+	})
+
+	t.Run("single file should be read", func(t *testing.T) {
+		s, err := NewCRDStoreFromFiles(crdsDir + "/widgets.yaml")
+		require.NoError(t, err)
+		require.Equal(t, []string{"example.com"}, s.GetGroups())
+	})
+}
+
+func TestGetAPIDefinition(t *testing.T) {
+	s, err := NewCRDStoreFromFiles(crdsDir)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		group   string
+		version string
+		kind    string
+		want    apis.APIVersionStatus
+		wantErr bool
+	}{
+		{
+			name:    "not served version should be deleted",
+			group:   "example.com",
+			version: "v1alpha1",
+			kind:    "Widget",
+			want: apis.APIVersionStatus{
+				DeletedVersion: "unknown",
+				Description:    "example.com/v1alpha1 Widget is not served anymore",
+				Replacement:    &apis.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Widget"},
+			},
+		},
+		{
+			name:    "deprecated version should use the deprecation warning",
+			group:   "example.com",
+			version: "v1beta1",
+			kind:    "Widget",
+			want: apis.APIVersionStatus{
+				DeprecationVersion: "unknown",
+				Description:        "example.com/v1beta1 Widget is deprecated; use example.com/v1 Widget",
+				Replacement:        &apis.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Widget"},
+			},
+		},
+		{
+			name:    "deprecated version without warning should use the default one",
+			group:   "acme.io",
+			version: "v1beta1",
+			kind:    "Gadget",
+			want: apis.APIVersionStatus{
+				DeprecationVersion: "unknown",
+				Description:        "acme.io/v1beta1 Gadget is deprecated",
+				Replacement:        &apis.GroupVersionKind{Group: "acme.io", Version: "v1", Kind: "Gadget"},
+			},
+		},
+		{
+			name:    "current version should not be deprecated",
+			group:   "example.com",
+			version: "v1",
+			kind:    "Widget",
+			want:    apis.APIVersionStatus{},
+		},
+		{
+			name:    "unknown kind should not be found",
+			group:   "example.com",
+			version: "v1",
+			kind:    "Gizmo",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.GetAPIDefinition(context.Background(), tt.group, tt.version, tt.kind)
+			if tt.wantErr {
+				require.ErrorIs(t, err, errors.ErrAPINotFound)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNewCRDStoreFromCluster(t *testing.T) {
+	crd := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "apiextensions.k8s.io/v1",
+			"kind":       "CustomResourceDefinition",
+			"metadata": map[string]interface{}{
+				"name": "certificates.cert-manager.io",
+			},
+			"spec": map[string]interface{}{
+				"group": "cert-manager.io",
+				"names": map[string]interface{}{
+					"kind":   "Certificate",
+					"plural": "certificates",
+				},
+				"versions": []interface{}{
+					map[string]interface{}{"name": "v1alpha2", "served": true, "storage": false, "deprecated": true},
+					map[string]interface{}{"name": "v1", "served": true, "storage": true},
+				},
+			},
+		},
+	}
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		crdgvr: "CustomResourceDefinitionList",
+	}, crd)
+
+	s, err := NewCRDStoreFromCluster(context.Background(), client)
+	require.NoError(t, err)
+
+	got, err := s.GetAPIDefinition(context.Background(), "cert-manager.io", "v1alpha2", "Certificate")
+	require.NoError(t, err)
+	require.Equal(t, "unknown", got.DeprecationVersion)
+
+	kind, err := s.GetKindForResource(context.Background(), "cert-manager.io", "v1alpha2", "certificates")
+	require.NoError(t, err)
+	require.Equal(t, "Certificate", kind)
+}
+
+func TestListAPIs(t *testing.T) {
+	s, err := NewCRDStoreFromFiles(crdsDir)
+	require.NoError(t, err)
+
+	got, err := s.ListAPIs(context.Background(), "acme.io")
+	require.NoError(t, err)
+	require.Equal(t, []apis.GroupVersionKind{
+		{Group: "acme.io", Version: "v1", Kind: "Gadget"},
+		{Group: "acme.io", Version: "v1beta1", Kind: "Gadget"},
+	}, got)
+}
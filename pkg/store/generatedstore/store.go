@@ -5,9 +5,14 @@ import (
 	"fmt"
 	"net/url"
 	"os"
+	"sort"
 
 	"github.com/Masterminds/semver/v3"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
 	apis "github.com/kubepug/kubepug/pkg/apis/v1alpha1"
+	"github.com/kubepug/kubepug/pkg/errors"
 	"github.com/kubepug/kubepug/pkg/utils"
 
 	generatedapi "github.com/kubepug/kubepug/generator/deprecations"
@@ -15,9 +20,15 @@ import (
 	json "github.com/goccy/go-json"
 )
 
+// unknownVersion is the deprecation version of the fields that don't tell since when they are deprecated
+const unknownVersion = "unknown"
+
 type StoreConfig struct {
 	// MinVerison defines the Kubernetes MinVersion that should be compared with this API
 	MinVersion string
+	// FromVersion defines the Kubernetes version currently running. When set, the store also tells
+	// if the replacement of an API is already available on it, so it can be migrated before upgrading
+	FromVersion string
 	// Path defines the path of the generated File
 	Path string
 	// internalPath defines the real path to be used on file location
@@ -26,12 +37,15 @@ type StoreConfig struct {
 }
 
 type GeneratedStore struct {
-	db               apis.APIGroups
+	db apis.APIGroups
+	// fields contains the deprecated fields of the APIs, indexed by group/version/kind
+	fields           map[string][]apis.FieldStatus
 	config           StoreConfig
 	requestedVersion *semver.Version
+	fromVersion      *semver.Version
 }
 
-func NewGeneratedStore(config StoreConfig) (*GeneratedStore, error) {
+func NewGeneratedStore(ctx context.Context, config StoreConfig) (*GeneratedStore, error) {
 	if config.Path == "" {
 		return nil, fmt.Errorf("generated json location cannot be null")
 	}
@@ -42,7 +56,7 @@ func NewGeneratedStore(config StoreConfig) (*GeneratedStore, error) {
 
 	urlLocation, err := url.Parse(config.Path)
 	if err == nil && (urlLocation.Scheme == "http" || urlLocation.Scheme == "https") {
-		config.internalPath, err = utils.DownloadGeneratedJSON(config.Path)
+		config.internalPath, err = utils.DownloadGeneratedJSON(ctx, config.Path)
 		if err != nil {
 			return nil, err
 		}
@@ -59,35 +73,86 @@ func NewGeneratedStore(config StoreConfig) (*GeneratedStore, error) {
 // NewGeneratedStoreFromBytes allows setting a reader as a database. It should contain
 // a valid Kubernetes generated data definition
 func NewGeneratedStoreFromBytes(data []byte, config StoreConfig) (*GeneratedStore, error) {
-	var parsedVersion *semver.Version
-	var err error
+	parsedVersion, err := parseVersion(config.MinVersion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse min version: %s", err)
+	}
 
-	if config.MinVersion != "" && config.MinVersion != "master" {
-		parsedVersion, err = semver.NewVersion(config.MinVersion)
-		if err != nil {
-			return nil, fmt.Errorf("failed to parse min version: %s", err)
-		}
+	fromVersion, err := parseMinorVersion(config.FromVersion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse from version: %s", err)
 	}
 
-	db, err := newInternalDatabase(data)
+	db, fields, err := newInternalDatabase(data)
 	if err != nil {
 		return nil, err
 	}
 	return &GeneratedStore{
 		db:               db,
+		fields:           fields,
 		config:           config,
 		requestedVersion: parsedVersion,
+		fromVersion:      fromVersion,
 	}, nil
 }
 
-func newInternalDatabase(data []byte) (apis.APIGroups, error) {
+// WithMinVersion returns a store sharing the same database, but comparing the APIs with
+// a different Kubernetes version. It allows comparing the same APIs with many versions without
+// reading the database again
+func (s *GeneratedStore) WithMinVersion(version string) (*GeneratedStore, error) {
+	parsedVersion, err := parseVersion(version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse min version: %s", err)
+	}
+
+	store := *s
+	store.config.MinVersion = version
+	store.requestedVersion = parsedVersion
+	return &store, nil
+}
+
+// WithFromVersion returns a store sharing the same database, but telling if the replacements
+// are available on a different current Kubernetes version
+func (s *GeneratedStore) WithFromVersion(version string) (*GeneratedStore, error) {
+	fromVersion, err := parseMinorVersion(version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse from version: %s", err)
+	}
+
+	store := *s
+	store.config.FromVersion = version
+	store.fromVersion = fromVersion
+	return &store, nil
+}
+
+// parseVersion parses a Kubernetes version, where empty and master mean the latest version
+func parseVersion(version string) (*semver.Version, error) {
+	if version == "" || version == "master" {
+		return nil, nil
+	}
+	return semver.NewVersion(version)
+}
+
+// parseMinorVersion parses a Kubernetes version keeping just its major and minor. APIs are
+// introduced on minor versions, and the versions reported by clusters may contain pre-release
+// suffixes (like v1.25.3-eks-1) that would make them lower than the minor version itself
+func parseMinorVersion(version string) (*semver.Version, error) {
+	parsedVersion, err := parseVersion(version)
+	if err != nil || parsedVersion == nil {
+		return nil, err
+	}
+	return semver.New(parsedVersion.Major(), parsedVersion.Minor(), 0, "", ""), nil
+}
+
+func newInternalDatabase(data []byte) (apis.APIGroups, map[string][]apis.FieldStatus, error) {
 	defs := []generatedapi.APIDeprecation{}
 	err := json.Unmarshal(data, &defs)
 	if err != nil {
-		return nil, fmt.Errorf("error parsing the JSON, file might be invalid: %v", err)
+		return nil, nil, fmt.Errorf("error parsing the JSON, file might be invalid: %v", err)
 	}
 
 	apigroup := make(apis.APIGroups)
+	fields := make(map[string][]apis.FieldStatus)
 	for k := range defs {
 		group := defs[k].Group
 		if group == "" {
@@ -115,8 +180,27 @@ func newInternalDatabase(data []byte) (apis.APIGroups, error) {
 		}
 
 		apigroup[group][defs[k].Kind][defs[k].Version] = status
+
+		for _, field := range defs[k].Fields {
+			key := fieldsKey(group, defs[k].Version, defs[k].Kind)
+			status := apis.FieldStatus{
+				Path:               field.Path,
+				Description:        field.Description,
+				DeprecationVersion: generateVersion(field.DeprecatedVersion.VersionMajor, field.DeprecatedVersion.VersionMinor),
+				DeletedVersion:     generateVersion(field.RemovedVersion.VersionMajor, field.RemovedVersion.VersionMinor),
+			}
+			// Fields deprecated just by their comments don't tell since when, so they are deprecated on any version
+			if status.DeprecationVersion == "" && status.DeletedVersion == "" {
+				status.DeprecationVersion = unknownVersion
+			}
+			fields[key] = append(fields[key], status)
+		}
 	}
-	return apigroup, nil
+	return apigroup, fields, nil
+}
+
+func fieldsKey(group, version, kind string) string {
+	return group + "/" + version + "/" + kind
 }
 
 func generateVersion(major, minor int) string {
@@ -154,10 +238,121 @@ func (s *GeneratedStore) GetAPIDefinition(_ context.Context, group, version, kin
 	result.IntroducedVersion = s.compareAndFill(apiversion.IntroducedVersion)
 	result.Description = apiversion.Description
 	result.Replacement = apiversion.Replacement
+	result.ReplacementAvailable = s.replacementAvailable(apiversion.Replacement)
 
 	return result, nil
 }
 
+// GetDeprecatedFields returns the fields of an API that are deprecated or deleted on the
+// Kubernetes version defined on the store. The fields that don't tell since when they are
+// deprecated are returned on any version, with an unknown deprecation version
+func (s *GeneratedStore) GetDeprecatedFields(_ context.Context, group, version, kind string) ([]apis.FieldStatus, error) {
+	if group == "" {
+		group = apis.CoreAPI
+	}
+
+	var fields []apis.FieldStatus
+	for _, field := range s.fields[fieldsKey(group, version, kind)] {
+		field.DeletedVersion = s.compareAndFill(field.DeletedVersion)
+		if field.DeprecationVersion != unknownVersion {
+			field.DeprecationVersion = s.compareAndFill(field.DeprecationVersion)
+		}
+		if field.DeletedVersion == "" && field.DeprecationVersion == "" {
+			continue
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// replacementAvailable tells if the replacement API was already introduced on the current version.
+// It returns nil when the current version or when the replacement introduction is unknown
+func (s *GeneratedStore) replacementAvailable(replacement *apis.GroupVersionKind) *bool {
+	if s.fromVersion == nil || replacement == nil {
+		return nil
+	}
+
+	group := replacement.Group
+	if group == "" {
+		group = apis.CoreAPI
+	}
+	status, ok := s.db[group][replacement.Kind][replacement.Version]
+	if !ok || status.IntroducedVersion == "" {
+		return nil
+	}
+
+	introduced, err := semver.NewVersion(status.IntroducedVersion)
+	if err != nil {
+		return nil
+	}
+	available := !s.fromVersion.LessThan(introduced)
+	return &available
+}
+
+// GetKindForResource finds the Kind served by a resource. The generated data does not contain
+// the resource names, so they are guessed from the Kinds the same way the API Server names them
+func (s *GeneratedStore) GetKindForResource(_ context.Context, group, version, resource string) (string, error) {
+	dbGroup := group
+	if dbGroup == "" {
+		dbGroup = apis.CoreAPI
+	}
+
+	apigroup, ok := s.db[dbGroup]
+	if !ok {
+		return "", errors.ErrAPINotFound
+	}
+
+	kinds := make([]string, 0, len(apigroup))
+	for kind := range apigroup {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+
+	for _, kind := range kinds {
+		if _, ok := apigroup[kind][version]; !ok {
+			continue
+		}
+		plural, _ := meta.UnsafeGuessKindToResource(schema.GroupVersionKind{Group: group, Version: version, Kind: kind})
+		if plural.Resource == resource {
+			return kind, nil
+		}
+	}
+
+	return "", errors.ErrAPINotFound
+}
+
+// ListAPIs returns the Group/Version/Kinds of a group, sorted by Kind and Version
+func (s *GeneratedStore) ListAPIs(_ context.Context, group string) ([]apis.GroupVersionKind, error) {
+	dbGroup := group
+	if dbGroup == "" {
+		dbGroup = apis.CoreAPI
+	}
+
+	list := make([]apis.GroupVersionKind, 0)
+	for kind, versions := range s.db[dbGroup] {
+		for version := range versions {
+			list = append(list, apis.GroupVersionKind{Group: group, Version: version, Kind: kind})
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Kind != list[j].Kind {
+			return list[i].Kind < list[j].Kind
+		}
+		return list[i].Version < list[j].Version
+	})
+	return list, nil
+}
+
+// IsReleased returns true when the release is the Kubernetes version compared with the store, or an
+// older one. Every valid release is included when the store is compared with the latest version
+func (s *GeneratedStore) IsReleased(release string) bool {
+	version, err := semver.NewVersion(release)
+	if err != nil {
+		return false
+	}
+	return s.requestedVersion == nil || !s.requestedVersion.LessThan(version)
+}
+
 // compareAndFillVersion gets the requested version and compares with apiVersion
 // If the requestedVersion is less than the detected version, it should be empty so the
 // API won't be tagged (as deprecated or deleted)
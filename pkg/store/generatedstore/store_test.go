@@ -5,12 +5,15 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/Masterminds/semver/v3"
 	apis "github.com/kubepug/kubepug/pkg/apis/v1alpha1"
+	"github.com/kubepug/kubepug/pkg/errors"
 	"github.com/kubepug/kubepug/pkg/store/mock"
 	"github.com/stretchr/testify/require"
+	"k8s.io/utils/ptr"
 )
 
 func Test_generateVersion(t *testing.T) {
@@ -50,12 +53,12 @@ func Test_generateVersion(t *testing.T) {
 
 func TestPopulateStruct(t *testing.T) {
 	t.Run("with invalid json file", func(t *testing.T) {
-		_, err := newInternalDatabase([]byte(mock.MockInvalidData))
+		_, _, err := newInternalDatabase([]byte(mock.MockInvalidData))
 		require.Error(t, err)
 	})
 
 	t.Run("with valid json file", func(t *testing.T) {
-		v, err := newInternalDatabase([]byte(mock.MockValidData))
+		v, _, err := newInternalDatabase([]byte(mock.MockValidData))
 		require.NoError(t, err)
 
 		require.Equal(t, v["extensions"]["DaemonSet"]["v1beta1"].DeprecationVersion, "1.8")
@@ -72,7 +75,7 @@ func TestPopulateStruct(t *testing.T) {
 	})
 
 	t.Run("without versions and replacements json file", func(t *testing.T) {
-		v, err := newInternalDatabase([]byte(mock.MockNoVersions))
+		v, _, err := newInternalDatabase([]byte(mock.MockNoVersions))
 		require.NoError(t, err)
 
 		require.Equal(t, v["extensions"]["DaemonSet"]["v1beta1"].DeprecationVersion, "")
@@ -107,16 +110,16 @@ func TestNewStoreFromHTTP(t *testing.T) {
 	defer ts.Close()
 
 	t.Run("with invalid path should fail", func(t *testing.T) {
-		_, err := NewGeneratedStore(StoreConfig{Path: ts.URL + "/notfound.json"})
+		_, err := NewGeneratedStore(context.Background(), StoreConfig{Path: ts.URL + "/notfound.json"})
 		require.Error(t, err)
 	})
 	t.Run("with invalid file content should fail", func(t *testing.T) {
-		_, err := NewGeneratedStore(StoreConfig{Path: ts.URL + "/datainvalid.json"})
+		_, err := NewGeneratedStore(context.Background(), StoreConfig{Path: ts.URL + "/datainvalid.json"})
 		require.Error(t, err)
 	})
 
 	t.Run("with valid remote file content should parse", func(t *testing.T) {
-		v, err := NewGeneratedStore(StoreConfig{Path: ts.URL + "/data.json"})
+		v, err := NewGeneratedStore(context.Background(), StoreConfig{Path: ts.URL + "/data.json"})
 		require.NoError(t, err)
 
 		require.Equal(t, v.db["extensions"]["DaemonSet"]["v1beta1"].DeprecationVersion, "1.8")
@@ -147,11 +150,11 @@ func TestNewStoreFromHTTP(t *testing.T) {
 
 func TestNewStoreFromFile(t *testing.T) {
 	t.Run("with no file path should return an error", func(t *testing.T) {
-		_, err := NewGeneratedStore(StoreConfig{Path: ""})
+		_, err := NewGeneratedStore(context.Background(), StoreConfig{Path: ""})
 		require.Error(t, err)
 	})
 	t.Run("with invalid file path should return an error", func(t *testing.T) {
-		_, err := NewGeneratedStore(StoreConfig{Path: "/xpto/blabla/123"})
+		_, err := NewGeneratedStore(context.Background(), StoreConfig{Path: "/xpto/blabla/123"})
 		require.Error(t, err)
 	})
 
@@ -160,7 +163,7 @@ func TestNewStoreFromFile(t *testing.T) {
 		require.NoError(t, err)
 		err = os.WriteFile(tmp+"/testfile", []byte(mock.MockValidData), 0o600)
 		require.NoError(t, err)
-		v, err := NewGeneratedStore(StoreConfig{Path: tmp + "/testfile", MinVersion: "v1.20"})
+		v, err := NewGeneratedStore(context.Background(), StoreConfig{Path: tmp + "/testfile", MinVersion: "v1.20"})
 		require.NoError(t, err)
 
 		results, err := v.GetAPIDefinition(context.TODO(), "admission.k8s.io", "v1beta1", "AdmissionReview")
@@ -386,3 +389,206 @@ func TestGeneratedStore_compareAndFill(t *testing.T) {
 		})
 	}
 }
+
+func TestIsReleased(t *testing.T) {
+	tests := []struct {
+		name       string
+		minVersion string
+		release    string
+		want       bool
+	}{
+		{name: "older releases should be released", minVersion: "v1.22.3", release: "1.16", want: true},
+		{name: "the same release should be released", minVersion: "v1.22.3", release: "1.22", want: true},
+		{name: "newer releases should not be released", minVersion: "v1.22.3", release: "1.25", want: false},
+		{name: "every release should be released on the latest version", minVersion: "", release: "1.99", want: true},
+		{name: "invalid releases should not be released", minVersion: "v1.22", release: "xpto", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, err := NewGeneratedStoreFromBytes([]byte(mock.MockValidData), StoreConfig{MinVersion: tt.minVersion})
+			require.NoError(t, err)
+			require.Equal(t, tt.want, store.IsReleased(tt.release))
+		})
+	}
+}
+
+func TestGetKindForResource(t *testing.T) {
+	store, err := NewGeneratedStoreFromBytes([]byte(mock.MockValidData), StoreConfig{})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		group    string
+		version  string
+		resource string
+		want     string
+		wantErr  bool
+	}{
+		{
+			name:     "resource of a group should be found",
+			group:    "extensions",
+			version:  "v1beta1",
+			resource: "daemonsets",
+			want:     "DaemonSet",
+		},
+		{
+			name:     "resource of the core group should be found",
+			version:  "v1",
+			resource: "blahpods",
+			want:     "BlahPod",
+		},
+		{
+			name:     "resource on a different version should not be found",
+			group:    "extensions",
+			version:  "v1",
+			resource: "daemonsets",
+			wantErr:  true,
+		},
+		{
+			name:     "unknown group should not be found",
+			group:    "xpto.io",
+			version:  "v1",
+			resource: "daemonsets",
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := store.GetKindForResource(context.Background(), tt.group, tt.version, tt.resource)
+			if tt.wantErr {
+				require.ErrorIs(t, err, errors.ErrAPINotFound)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestListAPIs(t *testing.T) {
+	store, err := NewGeneratedStoreFromBytes([]byte(mock.MockValidData), StoreConfig{})
+	require.NoError(t, err)
+
+	got, err := store.ListAPIs(context.Background(), "extensions")
+	require.NoError(t, err)
+	require.Equal(t, []apis.GroupVersionKind{{Group: "extensions", Version: "v1beta1", Kind: "DaemonSet"}}, got)
+
+	got, err = store.ListAPIs(context.Background(), "")
+	require.NoError(t, err)
+	require.Equal(t, []apis.GroupVersionKind{{Group: "", Version: "v1", Kind: "BlahPod"}}, got)
+
+	got, err = store.ListAPIs(context.Background(), "xpto.io")
+	require.NoError(t, err)
+	require.Empty(t, got)
+}
+
+func TestReplacementAvailable(t *testing.T) {
+	data, err := os.ReadFile("../../../test/testdata/plan/data.json")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		fromVersion string
+		group       string
+		version     string
+		kind        string
+		want        *bool
+	}{
+		{
+			name:        "replacement introduced after the current version is not available",
+			fromVersion: "v1.20.4",
+			group:       "batch",
+			version:     "v1beta1",
+			kind:        "CronJob",
+			want:        ptr.To(false),
+		},
+		{
+			name:        "replacement introduced on the current version is available, even on pre-releases",
+			fromVersion: "v1.21.0-eks-1",
+			group:       "batch",
+			version:     "v1beta1",
+			kind:        "CronJob",
+			want:        ptr.To(true),
+		},
+		{
+			name:    "availability is unknown without a current version",
+			group:   "batch",
+			version: "v1beta1",
+			kind:    "CronJob",
+		},
+		{
+			name:        "availability is unknown without a replacement",
+			fromVersion: "v1.24.0",
+			group:       "policy",
+			version:     "v1beta1",
+			kind:        "PodSecurityPolicy",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, err := NewGeneratedStoreFromBytes(data, StoreConfig{FromVersion: tt.fromVersion})
+			require.NoError(t, err)
+
+			got, err := store.GetAPIDefinition(context.Background(), tt.group, tt.version, tt.kind)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got.ReplacementAvailable)
+		})
+	}
+
+	t.Run("from version can be changed on an existing store", func(t *testing.T) {
+		store, err := NewGeneratedStoreFromBytes(data, StoreConfig{FromVersion: "v1.20.0"})
+		require.NoError(t, err)
+
+		newStore, err := store.WithFromVersion("v1.22.0")
+		require.NoError(t, err)
+		got, err := newStore.GetAPIDefinition(context.Background(), "batch", "v1beta1", "CronJob")
+		require.NoError(t, err)
+		require.Equal(t, ptr.To(true), got.ReplacementAvailable)
+
+		_, err = store.WithFromVersion("xpto")
+		require.ErrorContains(t, err, "failed to parse from version")
+	})
+}
+
+func TestGetDeprecatedFields(t *testing.T) {
+	data, err := os.ReadFile("../../../test/testdata/fields/data.json")
+	require.NoError(t, err)
+
+	paths := func(minVersion, group, version, kind string) map[string]apis.FieldStatus {
+		store, err := NewGeneratedStoreFromBytes(data, StoreConfig{MinVersion: minVersion})
+		require.NoError(t, err)
+
+		fields, err := store.GetDeprecatedFields(context.Background(), group, version, kind)
+		require.NoError(t, err)
+
+		found := make(map[string]apis.FieldStatus)
+		for _, field := range fields {
+			found[strings.Join(field.Path, ".")] = field
+		}
+		return found
+	}
+
+	t.Run("fields deprecated after the target version should not be returned", func(t *testing.T) {
+		fields := paths("v1.19.0", "apps", "v1", "Deployment")
+		require.Len(t, fields, 2)
+		require.Equal(t, "1.19", fields["spec.template.metadata.annotations.seccomp.security.alpha.kubernetes.io/pod"].DeprecationVersion)
+	})
+
+	t.Run("fields without versions should be deprecated on any version", func(t *testing.T) {
+		fields := paths("v1.1.0", "apps", "v1", "Deployment")
+		require.Len(t, fields, 1)
+		require.Equal(t, "unknown", fields["spec.template.spec.serviceAccount"].DeprecationVersion)
+		require.Empty(t, fields["spec.template.spec.serviceAccount"].DeletedVersion)
+	})
+
+	t.Run("fields removed on the target version should be deleted", func(t *testing.T) {
+		fields := paths("v1.25.0", "", "v1", "Pod")
+		require.Len(t, fields, 2)
+		require.Equal(t, "1.25", fields["metadata.clusterName"].DeletedVersion)
+		require.Empty(t, fields["spec.serviceAccount"].DeletedVersion)
+	})
+
+	t.Run("APIs without deprecated fields should return nothing", func(t *testing.T) {
+		require.Empty(t, paths("", "apps", "v1", "DaemonSet"))
+	})
+}
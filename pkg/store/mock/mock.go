@@ -1,6 +1,7 @@
 package mock
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/kubepug/kubepug/pkg/apis/v1alpha1"
@@ -68,7 +69,7 @@ func NewMockStore(loadData, shoulderr bool) *Store {
 	return m
 }
 
-func (m *Store) GetDeprecations() (deprecated, deleted []results.ResultItem, err error) {
+func (m *Store) GetDeprecations(_ context.Context) (deprecated, deleted []results.ResultItem, err error) {
 	if m.shouldError {
 		return nil, nil, fmt.Errorf("something weird happened")
 	}
@@ -1,6 +1,7 @@
 package mock
 
 import (
+	"context"
 	"reflect"
 	"testing"
 
@@ -47,7 +48,7 @@ func TestNewMockStore(t *testing.T) {
 			if !reflect.DeepEqual(storer, tt.want) {
 				t.Errorf("NewMockStore() = %v, want %v", storer, tt.want)
 			}
-			deprecated, deleted, err := storer.GetDeprecations()
+			deprecated, deleted, err := storer.GetDeprecations(context.Background())
 			if tt.shoulderr {
 				require.Error(t, err)
 				return
@@ -11,3 +11,57 @@ type DefinitionStorer interface {
 	// The error may be of type ErrAPINotFound, which means the API is deleted
 	GetAPIDefinition(ctx context.Context, group, version, kind string) (api.APIVersionStatus, error)
 }
+
+// KindResolver is implemented by the stores that are able to find the Kind served by
+// an API resource, for the inputs that only know the resource name (like audit logs)
+type KindResolver interface {
+	// GetKindForResource returns the Kind served by the resource, or an
+	// ErrAPINotFound error if the resource is not known by the store
+	GetKindForResource(ctx context.Context, group, version, resource string) (string, error)
+}
+
+// APILister is implemented by the stores able to list the APIs they know, for the
+// inputs that reference APIs without a Kind or a Version (like RBAC rules)
+type APILister interface {
+	// ListAPIs returns the Group/Version/Kinds known by the store on the group
+	ListAPIs(ctx context.Context, group string) ([]api.GroupVersionKind, error)
+}
+
+// FieldLister is implemented by the stores that know the deprecated fields of the APIs, for the
+// inputs able to verify the body of the objects
+type FieldLister interface {
+	// GetDeprecatedFields returns the fields of an API that are deprecated or deleted
+	GetDeprecatedFields(ctx context.Context, group, version, kind string) ([]api.FieldStatus, error)
+}
+
+// VersionComparer is implemented by the stores comparing the APIs with a Kubernetes version, for the
+// inputs that know when an API is removed without the store knowing the API (like the API Server metrics)
+type VersionComparer interface {
+	// IsReleased returns true when the release is the Kubernetes version compared with the store, or an older one
+	IsReleased(release string) bool
+}
+
+// GroupLister is implemented by the stores that know APIs outside of the groups
+// included by default (like the ones from CustomResourceDefinitions)
+type GroupLister interface {
+	// GetGroups returns the API groups known by the store
+	GetGroups() []string
+}
+
+// DefaultIgnoreGroups returns the groups that are ignored by default even when matching an included
+// prefix: externaldns (not core), anything on x-k8s.io, internal flowcontrol and the autoscaling group
+// that is actually a CRD (the real autoscaling is just autoscaling/version). A new slice is returned
+// on each call, so callers can append to it
+func DefaultIgnoreGroups() []string {
+	return []string{"externaldns.k8s.io", "x-k8s.io", "flowcontrol.apiserver.k8s.io", "autoscaling.k8s.io"}
+}
+
+// IncludeGroups returns the groups that should be included, plus the groups known by the
+// storer when it is a GroupLister. An empty include means all the groups are already included
+func IncludeGroups(storer DefinitionStorer, include []string) []string {
+	lister, ok := storer.(GroupLister)
+	if !ok || len(include) == 0 {
+		return include
+	}
+	return append(append([]string{}, include...), lister.GetGroups()...)
+}
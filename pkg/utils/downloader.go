@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"net/http"
@@ -10,10 +11,14 @@ import (
 )
 
 // From https://golangcode.com/download-a-file-from-a-url/ which was easier than create :P
-func downloadFile(filename, url string) error {
+func downloadFile(ctx context.Context, filename, url string) error {
 	// Get the data
 	log.Debugf("Downloading file from %s", url)
-	resp, err := http.Get(url) //nolint: gosec
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req) //nolint: gosec
 	if err != nil {
 		return err
 	}
@@ -36,14 +41,16 @@ func downloadFile(filename, url string) error {
 	return err
 }
 
-func DownloadGeneratedJSON(urlpath string) (filename string, err error) {
+// DownloadGeneratedJSON downloads the generated database to a temporary file, returning its location.
+// The download is aborted when the context is canceled
+func DownloadGeneratedJSON(ctx context.Context, urlpath string) (filename string, err error) {
 	tmpdir, err := os.MkdirTemp("", "kubepug")
 	if err != nil {
 		return "", err
 	}
 
 	filename = fmt.Sprintf("%s/data.json", tmpdir)
-	err = downloadFile(filename, urlpath)
+	err = downloadFile(ctx, filename, urlpath)
 	if err != nil {
 		return "", err
 	}
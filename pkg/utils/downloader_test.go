@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"context"
 	"net/http"
 	"net/http/httptest"
 	"os"
@@ -37,32 +38,40 @@ func TestDownloadGeneratedJSON(t *testing.T) {
 			require.NoError(t, err)
 		})
 		require.NoError(t, err)
-		f, err := DownloadGeneratedJSON(ts.URL + dataJSON)
+		f, err := DownloadGeneratedJSON(context.Background(), ts.URL+dataJSON)
 		require.Error(t, err)
 		require.Empty(t, f)
 	})
 
 	t.Run("download valid data", func(t *testing.T) {
-		f, err := DownloadGeneratedJSON(ts.URL + dataJSON)
+		f, err := DownloadGeneratedJSON(context.Background(), ts.URL+dataJSON)
 		require.NoError(t, err)
 		require.Contains(t, f, "kubepug")
 		require.Contains(t, f, "data.json")
 	})
 
 	t.Run("error on invalid data", func(t *testing.T) {
-		f, err := DownloadGeneratedJSON(ts.URL + "/notfound.json")
+		f, err := DownloadGeneratedJSON(context.Background(), ts.URL+"/notfound.json")
 		require.Error(t, err)
 		require.Empty(t, f)
 	})
 
 	t.Run("error on invalid url", func(t *testing.T) {
-		f, err := DownloadGeneratedJSON("http://127.0.0.1:xpto1")
+		f, err := DownloadGeneratedJSON(context.Background(), "http://127.0.0.1:xpto1")
 		require.Error(t, err)
 		require.Empty(t, f)
 	})
 
 	t.Run("try to create file on a forbidden place", func(t *testing.T) {
-		err := downloadFile("/tmp123/xpto", ts.URL+dataJSON)
+		err := downloadFile(context.Background(), "/tmp123/xpto", ts.URL+dataJSON)
 		require.Error(t, err)
 	})
+
+	t.Run("error on canceled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		f, err := DownloadGeneratedJSON(ctx, ts.URL+dataJSON)
+		require.ErrorIs(t, err, context.Canceled)
+		require.Empty(t, f)
+	})
 }
@@ -0,0 +1,32 @@
+package utils
+
+// HasField returns if the object contains the field on path. A "*" on the path matches
+// any item of a list or any key of a map, like spec.containers.*.securityContext
+func HasField(obj interface{}, path []string) bool {
+	if len(path) == 0 {
+		return obj != nil
+	}
+
+	switch value := obj.(type) {
+	case map[string]interface{}:
+		if path[0] != "*" {
+			field, ok := value[path[0]]
+			return ok && HasField(field, path[1:])
+		}
+		for _, field := range value {
+			if HasField(field, path[1:]) {
+				return true
+			}
+		}
+	case []interface{}:
+		if path[0] != "*" {
+			return false
+		}
+		for _, item := range value {
+			if HasField(item, path[1:]) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,78 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHasField(t *testing.T) {
+	obj := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]interface{}{
+				"seccomp.security.alpha.kubernetes.io/pod": "runtime/default",
+			},
+		},
+		"spec": map[string]interface{}{
+			"serviceAccount": "default",
+			"nodeName":       nil,
+			"containers": []interface{}{
+				map[string]interface{}{"name": "app"},
+				map[string]interface{}{"name": "sidecar", "securityContext": map[string]interface{}{"legacyProfile": true}},
+			},
+		},
+	}
+
+	tests := []struct {
+		name string
+		path []string
+		want bool
+	}{
+		{
+			name: "existing field should be found",
+			path: []string{"spec", "serviceAccount"},
+			want: true,
+		},
+		{
+			name: "missing field should not be found",
+			path: []string{"spec", "serviceAccountName"},
+			want: false,
+		},
+		{
+			name: "null field should not be found",
+			path: []string{"spec", "nodeName"},
+			want: false,
+		},
+		{
+			name: "map keys with dots should be found",
+			path: []string{"metadata", "annotations", "seccomp.security.alpha.kubernetes.io/pod"},
+			want: true,
+		},
+		{
+			name: "any list item should match a wildcard",
+			path: []string{"spec", "containers", "*", "securityContext", "legacyProfile"},
+			want: true,
+		},
+		{
+			name: "any map key should match a wildcard",
+			path: []string{"metadata", "*", "seccomp.security.alpha.kubernetes.io/pod"},
+			want: true,
+		},
+		{
+			name: "list items should only match a wildcard",
+			path: []string{"spec", "containers", "0", "name"},
+			want: false,
+		},
+		{
+			name: "fields inside scalars should not be found",
+			path: []string{"spec", "serviceAccount", "name"},
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, HasField(obj, tt.path))
+		})
+	}
+}